@@ -0,0 +1,103 @@
+package envconfig
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultFunc returns a default value computed when the field it is used for is read.
+type DefaultFunc func() (string, error)
+
+var (
+	defaultsMu sync.RWMutex
+	defaults   = map[string]DefaultFunc{
+		"hostname": os.Hostname,
+		"numcpu": func() (string, error) {
+			return strconv.Itoa(runtime.NumCPU()), nil
+		},
+		"random": func() (string, error) {
+			b := make([]byte, 8)
+			if _, err := rand.Read(b); err != nil {
+				return "", err
+			}
+			return hex.EncodeToString(b), nil
+		},
+	}
+)
+
+// RegisterDefault makes fn available as the default value provider named name, used with default=@name.
+// The providers hostname, numcpu and random, which returns 16 random hexadecimal digits, are always available.
+//
+// RegisterDefault panics if it is called twice with the same name.
+func RegisterDefault(name string, fn DefaultFunc) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+
+	if _, dup := defaults[name]; dup {
+		panic(fmt.Sprintf("envconfig: RegisterDefault called twice for %s", name))
+	}
+	defaults[name] = fn
+}
+
+// defaultProvider returns the name of the provider of a default value of the form @name, and whether there is one.
+// A default value starting with @@ is a literal one.
+func defaultProvider(defaultVal string) (string, bool) {
+	if !strings.HasPrefix(defaultVal, "@") || strings.HasPrefix(defaultVal, "@@") {
+		return "", false
+	}
+	return defaultVal[1:], true
+}
+
+// checkDefault returns an error if a default value of fld refers to a provider which is not registered.
+func (fld *Field) checkDefault() error {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+
+	vals := []string{fld.defaultVal}
+	for _, v := range fld.profileDefaults {
+		vals = append(vals, v)
+	}
+
+	for _, v := range vals {
+		name, ok := defaultProvider(v)
+		if _, found := defaults[name]; ok && !found {
+			return fmt.Errorf("envconfig: %s: unknown default provider %s", fld.Name(), name)
+		}
+	}
+	return nil
+}
+
+// providedValue is the value returned by the provider of the default value defaultVal.
+type providedValue struct {
+	defaultVal string
+	value      string
+}
+
+// evalDefault returns the default value defaultVal of fld, calling its provider if it has one.
+// The value returned by the provider is kept in fld, so that the provider is only called again once Read resets it.
+func (fld *Field) evalDefault(defaultVal string) (string, error) {
+	name, ok := defaultProvider(defaultVal)
+	if !ok {
+		return strings.TrimPrefix(defaultVal, "@"), nil
+	}
+	if p := fld.provided; p != nil && p.defaultVal == defaultVal {
+		return p.value, nil
+	}
+
+	defaultsMu.RLock()
+	fn := defaults[name]
+	defaultsMu.RUnlock()
+
+	str, err := fn()
+	if err != nil {
+		return "", fmt.Errorf("envconfig: %s: default provider %s: %w", fld.Name(), name, err)
+	}
+	fld.provided = &providedValue{defaultVal: defaultVal, value: str}
+	return str, nil
+}
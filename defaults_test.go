@@ -0,0 +1,108 @@
+package envconfig_test
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+var regionCalls int
+
+func init() {
+	envconfig.RegisterDefault("region", func() (string, error) {
+		regionCalls++
+		return "eu-west-1", nil
+	})
+	envconfig.RegisterDefault("broken", func() (string, error) {
+		return "", errors.New("unavailable")
+	})
+}
+
+func TestDefaultProviders(t *testing.T) {
+	regionCalls = 0
+
+	var conf struct {
+		Host     string `envconfig:"default=@hostname"`
+		Workers  int    `envconfig:"default=@numcpu"`
+		Instance string `envconfig:"default=@random"`
+		Region   string `envconfig:"default=@region"`
+		Handle   string `envconfig:"default=@@admin"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Equal(t, "@hostname", (*cinfo)[0].Default())
+
+	os.Setenv("REGION", "us-east-1")
+	err = cinfo.Read()
+	require.Nil(t, err)
+
+	hostname, _ := os.Hostname()
+	require.Equal(t, hostname, conf.Host)
+	require.Equal(t, runtime.NumCPU(), conf.Workers)
+	require.Len(t, conf.Instance, 16)
+	require.Equal(t, "us-east-1", conf.Region)
+	require.Equal(t, 0, regionCalls)
+	require.Equal(t, "@admin", conf.Handle)
+
+	os.Setenv("REGION", "")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "eu-west-1", conf.Region)
+	require.Equal(t, 1, regionCalls)
+}
+
+func TestDefaultProviderErrors(t *testing.T) {
+	var conf struct {
+		Zone string `envconfig:"default=@broken"`
+	}
+
+	err := envconfig.Init(&conf)
+	require.Equal(t, "envconfig: Zone: default provider broken: unavailable", err.Error())
+
+	var conf2 struct {
+		Zone string `envconfig:"default=@missing"`
+	}
+
+	_, err = envconfig.Parse(&conf2)
+	require.Equal(t, "envconfig: Zone: unknown default provider missing", err.Error())
+
+	require.Panics(t, func() {
+		envconfig.RegisterDefault("hostname", os.Hostname)
+	})
+}
+
+func TestDefaultProviderCalledOncePerRead(t *testing.T) {
+	var conf struct {
+		ID   string `envconfig:"ZZ_IID,default=@random"`
+		Name string `envconfig:"ZZ_INAME,default=svc-${ZZ_IID}"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, "svc-"+conf.ID, conf.Name)
+
+	first := conf.ID
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, "svc-"+conf.ID, conf.Name)
+	require.NotEqual(t, first, conf.ID)
+
+	var conf2 struct {
+		Zone *struct {
+			Region string `envconfig:"ZZ_REGION,default=@region"`
+		}
+		Label string `envconfig:"ZZ_LABEL,default=${ZZ_REGION}"`
+	}
+
+	regionCalls = 0
+	err = envconfig.InitWithOptions(&conf2, envconfig.Options{LeaveNil: true})
+	require.Nil(t, err)
+	require.Equal(t, "eu-west-1", conf2.Zone.Region)
+	require.Equal(t, "eu-west-1", conf2.Label)
+	require.Equal(t, 1, regionCalls)
+}
@@ -27,11 +27,20 @@ Then it's just a matter of setting the environment variables when calling your b
 
     ADDR=localhost PORT=6379 AUTH_KEY=foobar ./mybinary
 
+With a named struct type, Load returns a new value instead, and takes options as functions such as WithPrefix:
+
+    conf, err := envconfig.Load[Config](envconfig.WithPrefix("APP"))
+    if err != nil {
+        log.Fatalln(err)
+    }
+
+MustLoad is the same but panics on error.
+
 Layout of the conf struct
 
 Your conf struct must follow the following rules:
  - no unexported fields by default (can turn off with Options.AllowUnexported)
- - only supported types (no map fields for example)
+ - only supported types
 
 Naming of the keys
 
@@ -76,12 +85,64 @@ And, if that is not good enough for you, you always have the option to use a cus
 
 Now envconfig will only ever checks the environment variable _cassandraMyName_.
 
+A custom key spelled like a tag option, such as optional, port, hostport, nonzero, secret, inline, deprecated or reloadable,
+is read as that option. Options taking an argument, such as min= or conflicts=, are only recognised with the =,
+so a custom key spelled min is not affected. To use a key spelled like an option, escape its first character:
+
+    var conf struct {
+        ListenPort string `envconfig:"\\port"`
+    }
+
+The naming scheme can also be replaced for the whole struct with the NameMapper option.
+The package provides UpperSnake, which only tries CASSANDRA_SSL_CERT, NestedUpperSnake, which separates nested structs
+with a double underscore as in CASSANDRA__SSL_CERT, ExactNames, which tries Cassandra_SSLCert, and KebabCase, which tries cassandra.ssl-cert:
+
+    err := envconfig.InitWithOptions(&conf, envconfig.Options{NameMapper: envconfig.UpperSnake})
+
+Custom keys are not affected by the NameMapper.
+
+By default custom keys are used as they are written, ignoring the prefix and the enclosing structs.
+With the CustomNames option set to PrefixedCustomNames they follow the prefix,
+and with RelativeCustomNames they replace the field name and follow the keys of the enclosing structs.
+
+The name of a nested struct can be replaced in its keys with prefix=, and embedded structs can be flattened into their parent with inline,
+which lets a struct be shared between configurations:
+
+    type TLS struct {
+        Cert string
+        Key  string
+    }
+
+    type Common struct {
+        LogLevel string
+    }
+
+    var conf struct {
+        Common `envconfig:"inline"`
+        Server struct {
+            TLS TLS `envconfig:"prefix=Secure"`
+        }
+    }
+
+With that struct the keys are LOG_LEVEL, SERVER_SECURE_CERT and SERVER_SECURE_KEY.
+
+Several prefixes can be tried in turn with the Prefixes option, which helps while moving from one naming scheme to another:
+
+    err := envconfig.InitWithOptions(&conf, envconfig.Options{
+        Prefixes: []string{"NEWAPP", "LEGACY"},
+        Warn: func(fld *envconfig.Field, msg string) {
+            log.Println(msg) // envconfig: LEGACY_PORT uses a fallback prefix: use NEWAPP_PORT
+        },
+    })
+
+NEWAPP_PORT is tried first, then LEGACY_PORT. Field.Key reports the key a value was read from after Read.
 
 Content of the variables
 
-There are three types of content for a single variable:
+There are four types of content for a single variable:
  - for simple types, a single string representing the value, and parseable into the type.
  - for slices or arrays, a comma-separated list of strings. Each string must be parseable into the element type of the slice or array.
+ - for maps, a comma-separated list of key:value pairs. The key is everything up to the first colon.
  - for structs, a comma-separated list of specially formatted strings representing structs.
 
 Example of a valid slice value:
@@ -120,6 +181,29 @@ Here's an example:
 
 This will decode DATA to FOOBAR and put that into conf.Data.
 
+Use encoding= to choose another encoding. The supported encodings are base64 (the default), base64url, rawbase64 (unpadded), hex and raw (the value is used as is).
+Byte arrays are supported too, in which case the decoded value must have the length of the array:
+
+    var conf struct {
+        HMACKey   [32]byte `envconfig:"encoding=hex"`
+        JWTSecret []byte   `envconfig:"encoding=base64url"`
+    }
+
+Time values
+
+time.Time fields are parsed using RFC 3339 by default. Use layout= to choose another layout:
+
+    var conf struct {
+        Cutover time.Time `envconfig:"layout=DateOnly"`
+        Window  time.Time `envconfig:"layout=2006-01-02 15:04,tz=Europe/Paris"`
+        Expiry  time.Time `envconfig:"layout=unix"`
+    }
+
+The layout can be a Go time layout or the name of one of the time package constants (case insensitive), such as RFC3339, RFC1123 or DateOnly.
+The layouts unix, unixmilli, unixmicro and unixnano parse integer timestamps.
+
+Times without an explicit zone are interpreted as UTC, or in the location given with tz=.
+
 Optional values
 
 Sometimes you don't absolutely need a value. Here's how we tell envconfig a value is optional:
@@ -131,6 +215,20 @@ Sometimes you don't absolutely need a value. Here's how we tell envconfig a valu
 
 The two syntax are equivalent.
 
+Byte sizes and durations
+
+Integer fields tagged with unit=bytes, and fields of type ByteSize, accept human readable sizes:
+
+    var conf struct {
+        CacheSize envconfig.ByteSize `envconfig:"default=512MiB"`
+        MaxUpload int64              `envconfig:"unit=bytes,default=1.5GB"`
+    }
+
+Decimal units (kB, MB, GB, ...) are powers of 1000 and binary units (KiB, MiB, GiB, ...) are powers of 1024.
+A value without a unit is a number of bytes.
+
+time.Duration fields accept everything time.ParseDuration does, plus the units d (24 hours) and w (7 days), for example 2w or 1d12h.
+
 Default values
 
 Often times you have configuration keys which almost never changes, but you still want to be able to change them.
@@ -143,6 +241,235 @@ Here's to do this with envconfig:
         Timeout time.Duration `envconfig:"default=1m"`
     }
 
+Default values can refer to other keys with ${NAME}, or ${NAME:-fallback} to use fallback when NAME is not set:
+
+    var conf struct {
+        Host string `envconfig:"default=localhost"`
+        Addr string `envconfig:"default=${HOST}:${PORT:-8080}"`
+    }
+
+When NAME is a key of another field, the value or default of that field is used, otherwise NAME is looked up in the environment.
+Use $$ for a literal $. References in the values read from keys are only expanded with the Interpolate option.
+Read returns an error if references form a cycle.
+
+A default value of the form @name is computed by the provider registered with that name, only when no key is set,
+and at most once by each Read, so that references to the field see the same value as the field itself.
+The providers hostname, numcpu and random are built in, and others can be added with RegisterDefault:
+
+    envconfig.RegisterDefault("region", func() (string, error) {
+        return lookupRegion()
+    })
+
+    var conf struct {
+        Instance string `envconfig:"default=@hostname"`
+        Workers  int    `envconfig:"default=@numcpu"`
+        Region   string `envconfig:"default=@region"`
+    }
+
+The docs subpackage shows the name of the provider as the default value. Use @@ for a default value starting with a literal @.
+
+Default values can also be kept in code. With the ValuesAsDefaults option, the fields which are not zero when Parse is called
+keep their value when none of their keys is set, instead of requiring a key:
+
+    conf := Config{Timeout: time.Minute}
+    err := envconfig.InitWithOptions(&conf, envconfig.Options{ValuesAsDefaults: true})
+
+Such values take precedence over default= and are reported by Field.Default and the docs subpackage, as in 1m0s.
+
+Default values can depend on a profile, such as the environment the program runs in, with default.<profile>=:
+
+    var conf struct {
+        LogLevel string `envconfig:"default=info,default.prod=warn,default.dev=debug"`
+    }
+
+The profile is given by the Profile option, or read from the key named by the ProfileKey option, for example APP_ENV.
+Fields without a default value for the profile use their default= value.
+The tables of the docs subpackage have a default column for each profile.
+
+Validation
+
+Tag options can constrain the value of a field. The constraints are checked by Read once every field is read,
+and the error names the field and the constraint it does not satisfy:
+
+    var conf struct {
+        Workers int           `envconfig:"min=1,max=64"`
+        Timeout time.Duration `envconfig:"default=30s,max=5m"`
+        Level   string        `envconfig:"oneof=debug|info|warn"`
+        Name    string        `envconfig:"regex=[a-z]+(-[a-z]+)*"`
+        Port    int           `envconfig:"port"`
+        Listen  string        `envconfig:"hostport"`
+        Token   string        `envconfig:"nonzero"`
+    }
+
+The supported constraints are:
+ - min= and max= bound numbers, durations and times, and the length of strings, slices and maps.
+ - len= sets the exact length of strings, slices and maps.
+ - oneof= lists the accepted values separated by |.
+ - regex= is a regular expression the whole value must match. Remember to escape commas and backslashes.
+ - nonzero rejects the zero value of the type, even for optional fields with no value.
+ - port accepts a port number between 1 and 65535.
+ - hostport accepts a host:port pair, such as localhost:8080 or :8080.
+
+oneof=, regex=, port and hostport apply to each element of a slice.
+Apart from nonzero, constraints are only checked when the field has a value.
+
+Optional sections
+
+A nested struct can be gated by one of its bool fields with gate=:
+
+    var conf struct {
+        Metrics struct {
+            Enabled bool
+            Addr    string
+        } `envconfig:"gate=Enabled"`
+    }
+
+The gate field is read first and is optional. While it is false the other fields of the struct are not read at all,
+and once it is true they are read as usual, so METRICS_ADDR is only required when METRICS_ENABLED is true.
+
+By default nil pointers are allocated by Parse. With the LeaveNil option a nil pointer field is set by Read only
+when one of the fields behind it is set or has a default value, and is left nil otherwise:
+
+    var conf struct {
+        TLS *struct {
+            Cert string
+            Key  string
+        }
+    }
+
+Here conf.TLS stays nil unless TLS_CERT or TLS_KEY is set, and both are required once one of them is.
+
+Conditional requirements
+
+Some fields only make sense together. The following tag options express requirements between fields:
+ - required_if=Mode:tls makes an otherwise optional field mandatory when the field Mode is tls. Several values can be separated by |.
+ - requires=Password fails when this field is set but Password is not. Several fields can be separated by |.
+ - conflicts=Token fails when both this field and Token are set. Several fields can be separated by |.
+
+    var conf struct {
+        Auth struct {
+            Mode     string `envconfig:"oneof=none|tls|token"`
+            Cert     string `envconfig:"required_if=Mode:tls"`
+            Token    string `envconfig:"required_if=Mode:token,conflicts=Password"`
+            User     string `envconfig:"optional,requires=Password"`
+            Password string `envconfig:"optional"`
+        }
+    }
+
+Fields are referred to by their path, like Auth.Mode, either relative to the struct containing the field or from the top of the configuration struct.
+The condition of required_if looks at the value of the field, whether it comes from a key or a default.
+requires and conflicts only apply to fields whose value comes from a key, so that a default value never conflicts with another field,
+but a default value of the required field satisfies requires.
+
+Struct hooks
+
+Rules involving several fields are best written as methods of the struct holding them.
+Once every field is read and its constraints are checked, Read calls AfterRead on every struct implementing AfterReader,
+then Validate on every struct implementing Validator. Nested structs come before the structs containing them,
+and the error returned by a method is wrapped with the path of its struct:
+
+    type TLS struct {
+        Cert string `envconfig:"optional"`
+        Key  string `envconfig:"optional"`
+    }
+
+    func (c *TLS) Validate() error {
+        if c.Cert != "" && c.Key == "" {
+            return errors.New("key required when cert set")
+        }
+        return nil
+    }
+
+    var conf struct {
+        Server struct {
+            TLS TLS
+        }
+    }
+
+With this configuration, setting only SERVER_TLS_CERT makes Read return "envconfig: Server.TLS: key required when cert set".
+
+Renaming keys
+
+A key can be renamed without breaking existing deployments by keeping the old name as an alias.
+Aliases are tried after the usual keys and several can be separated by |:
+
+    var conf struct {
+        Name    string `envconfig:"APP_NAME,alias=NAME|SERVICE_NAME"`
+        Verbose bool   `envconfig:"optional,deprecated=use LOG_LEVEL"`
+    }
+
+When a value is read from an alias, or from any key of a field marked deprecated, the Warn function of Options is called:
+
+    cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{
+        Warn: func(fld *envconfig.Field, msg string) {
+            log.Println(msg) // envconfig: NAME is deprecated: use APP_NAME
+        },
+    })
+
+Reloading
+
+Reload reads the configuration again into a fresh copy of the struct, and only applies it once every field is read
+and every constraint and hook succeeded, so an invalid configuration leaves the current one untouched.
+Reload returns the fields whose value changed:
+
+    changes, err := cinfo.Reload()
+    if err != nil {
+        log.Println(err) // the previous configuration is still in use
+    }
+    for _, c := range changes {
+        log.Printf("%s changed from %s to %s", c.Field.Name(), c.Old, c.New)
+    }
+
+Fields behind a gate which closed, or a nil pointer which is left nil again, are reset to their zero value and returned as changes too.
+
+The values of fields marked with the secret tag option are replaced by Redacted in the changes, and their values and default values in the docs subpackage.
+
+With the FileKeys option, a field whose key is not set is read from the file named by the key with the _FILE suffix,
+as secrets mounted by Docker or Kubernetes are usually passed. Trailing line breaks are removed:
+
+    // DB_PASSWORD_FILE=/var/run/secrets/db/password
+    var conf struct {
+        DBPassword string `envconfig:"secret"`
+    }
+    cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{FileKeys: true})
+
+A Reloader runs Reload on demand and passes the changes, or the error, to the functions subscribed to it.
+Values are read from the environment, which does not change while a program runs, and from files, which do.
+WatchFiles reloads the configuration when the files named by the _FILE keys change, following symbolic links
+so that the atomic updates of mounted Kubernetes secrets are seen:
+
+    r := envconfig.NewReloader(cinfo)
+    r.Subscribe(func(changes []envconfig.Change, err error) {
+        // use the new credentials
+    })
+    stop := r.WatchFiles(10 * time.Second)
+    defer stop()
+
+Other files, read by parsers or unmarshalers, can be watched by passing their paths to WatchFiles.
+Default value providers are not called again by Reload, so that values such as @random do not change.
+
+ReloadOnSignal reloads the configuration when the process receives SIGHUP, or other signals, and ReloadOn when a value is received from a channel.
+
+Some values cannot change while a program runs, such as the port it listens on. With the RestrictReload option,
+only the fields with the reloadable tag option may change, and Reload fails without applying anything if any other field changed:
+
+    var conf struct {
+        Port     int
+        LogLevel string `envconfig:"reloadable"`
+    }
+
+Reload sets the fields of the conf object one after the other, so code reading them while they are reloaded may see
+a mix of old and new values. Value holds a configuration which is replaced at once instead:
+
+    conf, err := envconfig.NewValue[Config](envconfig.Options{})
+    if err != nil {
+        log.Fatalln(err)
+    }
+    envconfig.NewReloader(conf).ReloadOnSignal()
+
+    // in request handlers
+    cfg := conf.Load()
+
 Notes
 
 Notes allows you to add small bits of text with a configuration key.
@@ -176,10 +503,23 @@ envconfig supports the following list of types:
  - uintX
  - floatX
  - time.Duration
+ - time.Time
  - pointers to all of the above types
 
 Notably, we don't (yet) support complex types simply because I had no use for it yet.
 
+Integer and float values must fit in the size of the field's type, so 300 is rejected for an int8 field instead of wrapping.
+Integers are parsed in base 10 by default. Use base= to change the base.
+With base=0 the base is implied by the prefix (0x, 0o or 0b) and underscores may separate digits:
+
+    var conf struct {
+        Mask  uint32 `envconfig:"base=0"`
+        Limit int    `envconfig:"base=0"`
+    }
+
+    os.Setenv("MASK", "0xff_ff")
+    os.Setenv("LIMIT", "1_000_000")
+
 Custom unmarshaler
 
 When the standard types are not enough, you will want to use a custom unmarshaler for your types.
@@ -206,6 +546,62 @@ You do this by implementing Unmarshaler on your type. Here's an example:
         return nil
     }
 
+If the type needs to know which field it is setting, implement FieldUnmarshaler instead.
+The Field gives access to the field's name, keys and default, and to any key=value tag option through Param,
+so one type can be configured per field:
+
+    type interval time.Duration
+
+    func (i *interval) UnmarshalField(fld *envconfig.Field, s string) error {
+        unit, _ := fld.Param("unit")
+        ...
+    }
+
+    var conf struct {
+        Poll interval `envconfig:"unit=ms"`
+    }
+
+Interface fields
+
+A field of an interface type can be set to one of several implementations registered with Register.
+The implementation is selected by name with a discriminator key, made of the field name followed by Driver,
+and its fields are read like a nested struct named after the implementation:
+
+    type Storage interface { ... }
+
+    type S3 struct {
+        Bucket string
+        Region string `envconfig:"default=us-east-1"`
+    }
+
+    func init() {
+        envconfig.Register((*Storage)(nil), "s3", &S3{})
+    }
+
+    var conf struct {
+        Storage Storage `envconfig:"default=s3"`
+    }
+
+    os.Setenv("STORAGE_DRIVER", "s3")
+    os.Setenv("STORAGE_S3_BUCKET", "assets")
+
+Tag options of the interface field, such as default=, optional and a custom name, apply to the discriminator key.
+Only the fields of the selected implementation are read, and an unknown name is an error.
+
+Custom parsers
+
+Types you can't implement Unmarshaler on, such as types from other packages, can be handled with Options.Parsers.
+A parser registered for a type applies to fields, pointers, slice elements and map keys and values of that type,
+and takes precedence over the built-in parsing:
+
+    err := envconfig.InitWithOptions(&conf, envconfig.Options{
+        Parsers: map[reflect.Type]envconfig.ParseFunc{
+            reflect.TypeOf((*url.URL)(nil)): func(s string) (interface{}, error) {
+                return url.Parse(s)
+            },
+        },
+    })
+
 ConfInfo object
 
 A ConfInfo object can be passed to functions in the `docs` subpackage to generate documentation.
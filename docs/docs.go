@@ -9,23 +9,106 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"html/template"
 	"io"
+	"sort"
 	"strings"
 )
 
 func noteOptional(fld *envconfig.Field) string {
-	note := fld.Note()
-	if !fld.Optional() {
-		return note
+	var parts []string
+	if fld.Optional() {
+		parts = append(parts, "Optional.")
 	}
-	if note == "" {
-		return "Optional."
+	if note := fld.Note(); note != "" {
+		parts = append(parts, note)
 	}
-	return "Optional. " + note
+	if msg, ok := fld.Deprecated(); ok && msg != "" {
+		parts = append(parts, "Deprecated: "+msg+".")
+	} else if ok {
+		parts = append(parts, "Deprecated.")
+	}
+	if aliases := fld.Aliases(); aliases != nil {
+		parts = append(parts, "Aliases: "+strings.Join(aliases, ", ")+".")
+	}
+	if enc := fld.Encoding(); enc != "" {
+		parts = append(parts, "Encoding: "+enc+".")
+	}
+	for _, r := range fld.Relations() {
+		switch r.Kind {
+		case "required_if":
+			parts = append(parts, "Required when "+r.Field.Name()+" is "+strings.Join(r.Values, " or ")+".")
+		case "requires":
+			parts = append(parts, "Requires "+r.Field.Name()+".")
+		case "conflicts":
+			parts = append(parts, "Conflicts with "+r.Field.Name()+".")
+		}
+	}
+	if rules := fld.Rules(); rules != nil {
+		parts = append(parts, "Constraints: "+strings.Join(rules, ", ")+".")
+	}
+	if choices := fld.Choices(); choices != nil {
+		parts = append(parts, "One of: "+strings.Join(choices, ", ")+".")
+	}
+	if cond := fld.Condition(); cond != "" {
+		parts = append(parts, "Used when "+cond+".")
+	}
+	return strings.Join(parts, " ")
 }
 
+// keysUpper returns the keys of fld, leaving out any key whose upper case form is also a key.
 func keysUpper(fld *envconfig.Field) []string {
 	keys := fld.Keys()
-	return keys[:len(keys)/2]
+
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+
+	var res []string
+	for _, key := range keys {
+		if upper := strings.ToUpper(key); upper == key || !set[upper] {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// redact returns str, a value of fld, or envconfig.Redacted if fld is secret.
+func redact(fld *envconfig.Field, str string) string {
+	if fld.Secret() && str != "" {
+		return envconfig.Redacted
+	}
+	return str
+}
+
+// fieldValue returns the value of fld, hiding it if it is secret.
+func fieldValue(fld *envconfig.Field) string {
+	return redact(fld, fld.Value())
+}
+
+// fieldDefault returns the default value of fld, hiding it if it is secret.
+func fieldDefault(fld *envconfig.Field) string {
+	return redact(fld, fld.Default())
+}
+
+// fieldProfileDefault returns the default value of fld for the profile p, hiding it if it is secret.
+func fieldProfileDefault(fld *envconfig.Field, p string) string {
+	return redact(fld, fld.ProfileDefault(p))
+}
+
+// profiles returns the sorted names of the profiles any field of cinfo has a default value for.
+func profiles(cinfo *envconfig.ConfInfo) []string {
+	set := make(map[string]bool)
+	var res []string
+	for _, fld := range *cinfo {
+		for _, p := range fld.Profiles() {
+			if !set[p] {
+				set[p] = true
+				res = append(res, p)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
 }
 
 // TextTable writes each field in the configuration struct as a row in a text table.
@@ -54,13 +137,23 @@ func TextTableWithWidth(w io.Writer, cinfo *envconfig.ConfInfo, maxwidth int) {
 // maxwidth sets the maximum number of charaters wide each column in the table can be.
 func TextTableWithOptions(w io.Writer, cinfo *envconfig.ConfInfo, table *tablewriter.Table, maxwidth int) {
 	table.SetAutoWrapText(false)
-	table.SetHeader([]string{"Keys", "Value", "Default", "Note"})
+
+	header := []string{"Keys", "Value", "Default"}
+	for _, p := range profiles(cinfo) {
+		header = append(header, "Default ("+p+")")
+	}
+	table.SetHeader(append(header, "Note"))
 
 	for _, fld := range *cinfo {
-		value, _ := tablewriter.WrapString(fld.Value(), maxwidth)
-		deflt, _ := tablewriter.WrapString(fld.Default(), maxwidth)
+		value, _ := tablewriter.WrapString(fieldValue(fld), maxwidth)
+		deflt, _ := tablewriter.WrapString(fieldDefault(fld), maxwidth)
+		row := []string{strings.Join(keysUpper(fld), "\n"), strings.Join(value, "\n"), strings.Join(deflt, "\n")}
+		for _, p := range profiles(cinfo) {
+			pdeflt, _ := tablewriter.WrapString(fieldProfileDefault(fld, p), maxwidth)
+			row = append(row, strings.Join(pdeflt, "\n"))
+		}
 		note, _ := tablewriter.WrapString(noteOptional(fld), maxwidth)
-		table.Append([]string{strings.Join(keysUpper(fld), "\n"), strings.Join(value, "\n"), strings.Join(deflt, "\n"), strings.Join(note, "\n")})
+		table.Append(append(row, strings.Join(note, "\n")))
 	}
 
 	table.Render()
@@ -77,15 +170,23 @@ func init() {
 // Use HTMLTableWithTemplate if you want to embed the table within a custom template.
 func HTMLTableWithTemplate(t *template.Template) (*template.Template, error) {
 	funcmap := template.FuncMap{
-		"envconfigNoteOptional": noteOptional,
-		"envconfigKeysUpper":    keysUpper,
+		"envconfigNoteOptional":   noteOptional,
+		"envconfigKeysUpper":      keysUpper,
+		"envconfigProfiles":       profiles,
+		"envconfigValue":          fieldValue,
+		"envconfigDefault":        fieldDefault,
+		"envconfigProfileDefault": fieldProfileDefault,
 	}
 	return t.Funcs(funcmap).Parse(tmpl_src)
 }
 
 // HTMLTable writes each field in the configuration struct as a row in an HTML table.
 func HTMLTable(w io.Writer, cinfo *envconfig.ConfInfo) error {
-	if t, err := HTMLTableWithTemplate(base_tmpl); err == nil {
+	base, err := base_tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	if t, err := HTMLTableWithTemplate(base); err == nil {
 		return t.Execute(w, cinfo)
 	} else {
 		return err
@@ -107,15 +208,17 @@ var tmpl_src = `{{define "envconfig"}}<table>
 		<tr>
 			<th>Keys</th>
 			<th>Value</th>
-			<th>Default</th>
+			<th>Default</th>{{range envconfigProfiles $}}
+			<th>Default ({{.}})</th>{{end}}
 			<th>Note</th>
 		</tr>
 	</thead>
-	<tbody>{{range .}}
+	<tbody>{{range $field := .}}
 		<tr>
 			<th>{{range $index, $element := envconfigKeysUpper .}}{{if ne $index 0}}<br>{{end}}{{$element}}{{end}}</th>
-			<th>{{.Value}}</th>
-			<th>{{.Default}}</th>
+			<th>{{envconfigValue .}}</th>
+			<th>{{envconfigDefault .}}</th>{{range $p := envconfigProfiles $}}
+			<th>{{envconfigProfileDefault $field $p}}</th>{{end}}
 			<th>{{envconfigNoteOptional .}}</th>
 		</tr>{{end}}
 	</tbody>
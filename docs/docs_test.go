@@ -0,0 +1,124 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteOptional(t *testing.T) {
+	var conf struct {
+		Port    int    `envconfig:"default=8080,port,min=1024,note=Listen port."`
+		Level   string `envconfig:"optional,oneof=debug|info"`
+		HMACKey []byte `envconfig:"encoding=hex"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+
+	require.Equal(t, "Listen port. Constraints: port, min=1024.", noteOptional((*cinfo)[0]))
+	require.Equal(t, "Optional. Constraints: oneof=debug|info.", noteOptional((*cinfo)[1]))
+	require.Equal(t, "Encoding: hex.", noteOptional((*cinfo)[2]))
+}
+
+func TestKeysUpper(t *testing.T) {
+	var conf struct {
+		SSLCert string
+		Name    string `envconfig:"appName"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Equal(t, []string{"SSLCERT", "SSL_CERT"}, keysUpper((*cinfo)[0]))
+	require.Equal(t, []string{"appName"}, keysUpper((*cinfo)[1]))
+
+	cinfo, err = envconfig.ParseWithOptions(&conf, envconfig.Options{NameMapper: envconfig.UpperSnake})
+	require.Nil(t, err)
+	require.Equal(t, []string{"SSL_CERT"}, keysUpper((*cinfo)[0]))
+
+	cinfo, err = envconfig.ParseWithOptions(&conf, envconfig.Options{NameMapper: envconfig.KebabCase})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ssl-cert"}, keysUpper((*cinfo)[0]))
+}
+
+func TestNoteRelations(t *testing.T) {
+	var conf struct {
+		Mode     string
+		Cert     string `envconfig:"required_if=Mode:tls|mtls"`
+		User     string `envconfig:"optional,requires=Password,conflicts=Token"`
+		Password string `envconfig:"optional"`
+		Token    string `envconfig:"optional"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+
+	require.Equal(t, "Optional. Required when Mode is tls or mtls.", noteOptional((*cinfo)[1]))
+	require.Equal(t, "Optional. Requires Password. Conflicts with Token.", noteOptional((*cinfo)[2]))
+}
+
+func TestNoteDeprecated(t *testing.T) {
+	var conf struct {
+		Name    string `envconfig:"NEW_NAME,alias=OLD_NAME"`
+		Verbose bool   `envconfig:"optional,deprecated=use LOG_LEVEL"`
+		Debug   bool   `envconfig:"optional,deprecated"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+
+	require.Equal(t, "Aliases: OLD_NAME.", noteOptional((*cinfo)[0]))
+	require.Equal(t, "Optional. Deprecated: use LOG_LEVEL.", noteOptional((*cinfo)[1]))
+	require.Equal(t, "Optional. Deprecated.", noteOptional((*cinfo)[2]))
+}
+
+func TestProfileColumns(t *testing.T) {
+	var conf struct {
+		Level string `envconfig:"default=info,default.prod=warn,default.dev=debug"`
+		Port  int    `envconfig:"default=8080,default.staging=8081"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Equal(t, []string{"dev", "prod", "staging"}, profiles(cinfo))
+
+	table := TextTableString(cinfo)
+	require.Contains(t, table, "DEFAULT (DEV)")
+	require.Contains(t, table, "DEFAULT (STAGING)")
+
+	html, err := HTMLTableString(cinfo)
+	require.Nil(t, err)
+	require.Contains(t, html, "<th>Default (prod)</th>")
+	require.Contains(t, html, "<th>warn</th>")
+	require.Contains(t, html, "<th>8081</th>")
+}
+
+func TestSecretValue(t *testing.T) {
+	var conf struct {
+		Token string `envconfig:"DOCS_TOKEN,secret,default=hunter2"`
+		User  string `envconfig:"DOCS_USER,optional,secret"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+
+	require.Equal(t, envconfig.Redacted, fieldValue((*cinfo)[0]))
+	require.Equal(t, "", fieldValue((*cinfo)[1]))
+	require.Contains(t, TextTableString(cinfo), "| [redacted] |")
+
+	conf2 := struct {
+		Token string `envconfig:"DOCS_TOKEN,secret,default.prod=hunter3"`
+	}{Token: "hunter2"}
+
+	cinfo, err = envconfig.ParseWithOptions(&conf2, envconfig.Options{ValuesAsDefaults: true})
+	require.Nil(t, err)
+
+	require.Equal(t, envconfig.Redacted, fieldDefault((*cinfo)[0]))
+	require.Equal(t, envconfig.Redacted, fieldProfileDefault((*cinfo)[0], "prod"))
+	require.NotContains(t, TextTableString(cinfo), "hunter")
+	html, err := HTMLTableString(cinfo)
+	require.Nil(t, err)
+	require.NotContains(t, html, "hunter")
+}
@@ -171,3 +171,50 @@ func ExampleHTMLTableWithTemplate() {
 	// 	</body>
 	// </html>
 }
+
+func ExampleHTMLTable_encoding() {
+	var conf struct {
+		Key  []byte `envconfig:"encoding=hex,note=Request signing key."`
+		Seed []byte `envconfig:"optional"`
+	}
+
+	os.Setenv("HMAC_KEY", "deadbeef")
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "HMAC"})
+	if err != nil {
+		panic(err)
+	}
+
+	if err = cinfo.Read(); err != nil {
+		panic(err)
+	}
+
+	if err = docs.HTMLTable(os.Stdout, cinfo); err != nil {
+		panic(err)
+	}
+	// Output:
+	// <table>
+	// 	<thead>
+	// 		<tr>
+	// 			<th>Keys</th>
+	// 			<th>Value</th>
+	// 			<th>Default</th>
+	// 			<th>Note</th>
+	// 		</tr>
+	// 	</thead>
+	// 	<tbody>
+	// 		<tr>
+	// 			<th>HMAC_KEY</th>
+	// 			<th>deadbeef</th>
+	// 			<th></th>
+	// 			<th>Request signing key. Encoding: hex.</th>
+	// 		</tr>
+	// 		<tr>
+	// 			<th>HMAC_SEED</th>
+	// 			<th></th>
+	// 			<th></th>
+	// 			<th>Optional. Encoding: base64.</th>
+	// 		</tr>
+	// 	</tbody>
+	// </table>
+}
@@ -2,7 +2,10 @@ package envconfig
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -26,20 +29,115 @@ func (cinfo *ConfInfo) append(fld *Field) {
 }
 
 // Read reads the configuration from environment variables and populates the conf object.
+// Once every field is read and its constraints are checked,
+// Read calls the AfterRead and Validate methods of the structs implementing AfterReader and Validator.
+// The default value providers of a field are called at most once by each Read.
 func (cinfo *ConfInfo) Read() error {
 	for _, fld := range *cinfo {
+		fld.provided = nil
+	}
+	return cinfo.read()
+}
+
+// read implements Read, reusing the values default value providers returned since the fields were last reset.
+func (cinfo *ConfInfo) read() error {
+	if len(*cinfo) > 0 {
+		if err := (*cinfo)[0].state.updateSlots(); err != nil {
+			return err
+		}
+	}
+	for _, fld := range *cinfo {
+		if !fld.gate.isOpen() {
+			fld.strValue = ""
+			continue
+		}
 		if err := fld.setValue(); err != nil {
 			return err
 		}
 	}
+	if err := cinfo.setVariants(); err != nil {
+		return err
+	}
+	for _, fld := range *cinfo {
+		if err := fld.checkRelations(); err != nil {
+			return err
+		}
+	}
+	for _, fld := range *cinfo {
+		if fld.gate.isOpen() {
+			if err := fld.checkRules(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(*cinfo) > 0 {
+		return (*cinfo)[0].state.runHooks()
+	}
 	return nil
 }
 
+// state is shared by all the fields of a ConfInfo.
+type state struct {
+	nodes       []*node
+	warnFn      func(fld *Field, msg string)
+	prefixes    []fieldName
+	slots       []*slot
+	profileName string
+	profileKey  string
+	root        reflect.Value
+	opts        Options
+	config      *ConfInfo
+	interpolate bool
+}
+
+// profile returns the name of the profile whose default values are used.
+func (st *state) profile() string {
+	if st.profileName == "" && st.profileKey != "" {
+		return os.Getenv(st.profileKey)
+	}
+	return st.profileName
+}
+
+func (st *state) warn(fld *Field, msg string) {
+	if st.warnFn != nil {
+		st.warnFn(fld, msg)
+	}
+}
+
 type context struct {
-	config          *ConfInfo
-	name            fieldName
-	optional        bool
-	allowUnexported bool
+	config           *ConfInfo
+	state            *state
+	depth            int
+	name             fieldName
+	optional         bool
+	allowUnexported  bool
+	parsers          map[reflect.Type]ParseFunc
+	gate             *gate
+	gateOn           string
+	names            NameMapper
+	path             fieldName
+	prefix           fieldName
+	customNames      CustomNameMode
+	valuesAsDefaults bool
+	leaveNil         bool
+}
+
+// sub returns the context for the fields of the nested struct field named name with the tag t.
+func (ctx *context) sub(name string, t *tag) *context {
+	sub := *ctx
+	sub.name = ctx.name.Append(name)
+	sub.depth = ctx.depth + 1
+	sub.optional = ctx.optional || t.optional
+	sub.gateOn = t.gate
+	switch {
+	case t.inline:
+		sub.path = ctx.path
+	case t.prefix != "":
+		sub.path = ctx.path.Append(t.prefix)
+	default:
+		sub.path = ctx.path.Append(name)
+	}
+	return &sub
 }
 
 // Unmarshaler is the interface implemented by objects that can unmarshal a environment variable string of themselves.
@@ -47,17 +145,89 @@ type Unmarshaler interface {
 	Unmarshal(s string) error
 }
 
+// FieldUnmarshaler is the interface implemented by objects that can unmarshal a environment variable string of themselves
+// and need information about the field being set, such as its name, keys or tag parameters.
+// FieldUnmarshaler takes precedence over Unmarshaler.
+type FieldUnmarshaler interface {
+	UnmarshalField(fld *Field, s string) error
+}
+
+// ParseFunc parses the string s into a value of the type it is registered for in Options.Parsers.
+type ParseFunc func(s string) (interface{}, error)
+
+// CustomNameMode determines how custom names set in tags are turned into keys.
+type CustomNameMode int
+
+const (
+	// ExactCustomNames uses custom names as keys as they are written.
+	ExactCustomNames CustomNameMode = iota
+	// PrefixedCustomNames uses custom names after Options.Prefix, as if the field was at the top of the struct.
+	PrefixedCustomNames
+	// RelativeCustomNames uses custom names in place of the field name, after the keys of the enclosing structs.
+	RelativeCustomNames
+)
+
 // Options is used to customize the behavior of envconfig. Use it with InitWithOptions.
 type Options struct {
 	// Prefix allows specifying a prefix for each key.
 	Prefix string
 
+	// Prefixes lists prefixes to try in turn when a key is not found, after Prefix if it is set.
+	// The first prefix is used in the names of the fields and in messages;
+	// Warn is called when a value is read with one of the others.
+	// An empty string stands for keys without a prefix.
+	Prefixes []string
+
 	// AllOptional determines whether to not throw errors by default for any key
 	// that is not found. AllOptional=true means errors will not be thrown.
 	AllOptional bool
 
 	// AllowUnexported allows unexported fields to be present in the passed config.
 	AllowUnexported bool
+
+	// NameMapper chooses the keys tried for each field. DefaultNames is used if it is nil.
+	NameMapper NameMapper
+
+	// CustomNames determines how custom names set in tags are turned into keys.
+	// By default they are used as they are written.
+	// When they are prefixed or relative, they are passed to the NameMapper like field names.
+	CustomNames CustomNameMode
+
+	// Interpolate expands ${NAME} and ${NAME:-fallback} references in the values read from keys,
+	// as is always done in default values. See the package documentation for details.
+	Interpolate bool
+
+	// FileKeys makes Read look up the key with the _FILE suffix when a key is not set, such as DB_PASSWORD_FILE for DB_PASSWORD,
+	// and read the value from the file it names, as secrets mounted by Docker or Kubernetes are usually passed.
+	FileKeys bool
+
+	// ValuesAsDefaults makes the values fields hold when Parse is called default values, when they are not zero.
+	// They take precedence over the default values set in tags.
+	ValuesAsDefaults bool
+
+	// LeaveNil leaves nil pointer fields nil when none of the keys of the fields behind them is set and none has a default value.
+	// Their fields are still returned by Parse, and are only required once one of them is set.
+	LeaveNil bool
+
+	// Profile selects the default values set with default.<profile>= tag options, such as default.prod=warn.
+	// Fields without a default value for the profile use their default= value.
+	Profile string
+
+	// ProfileKey names a key, such as APP_ENV, read by Read to select the profile when Profile is empty.
+	ProfileKey string
+
+	// RestrictReload makes ConfInfo.Reload reject changes to fields without the reloadable tag option,
+	// for values which cannot change while the program runs, such as the port it listens on.
+	RestrictReload bool
+
+	// Warn, if not nil, is called with a message when a value is read from a deprecated key or an alias.
+	Warn func(fld *Field, msg string)
+
+	// Parsers maps types to the functions used to parse them.
+	// A parser takes precedence over an Unmarshaler and over the built-in parsing of the type,
+	// and applies wherever the type is used: fields, pointers, slice elements and map keys and values.
+	// The value returned by a parser must be assignable to the type it is registered for.
+	Parsers map[reflect.Type]ParseFunc
 }
 
 // Init reads the configuration from environment variables and populates the conf object.
@@ -117,33 +287,96 @@ func ParseWithOptions(conf interface{}, opts Options) (*ConfInfo, error) {
 		return nil, ErrInvalidValueKind
 	}
 
-	name := fieldName{}
+	prefixes := opts.Prefixes
 	if opts.Prefix != "" {
-		name = name.Append(opts.Prefix)
+		prefixes = append([]string{opts.Prefix}, prefixes...)
 	}
 
 	cinfo := &ConfInfo{}
-	return cinfo, readStruct(elem, &context{
-		config:          cinfo,
-		name:            name,
-		optional:        opts.AllOptional,
-		allowUnexported: opts.AllowUnexported,
+	st := &state{
+		warnFn:      opts.Warn,
+		config:      cinfo,
+		interpolate: opts.Interpolate,
+		profileName: opts.Profile,
+		profileKey:  opts.ProfileKey,
+		root:        elem,
+		opts:        opts,
+	}
+	for _, prefix := range prefixes {
+		p := fieldName{}
+		if prefix != "" {
+			p = p.Append(prefix)
+		}
+		st.prefixes = append(st.prefixes, p)
+	}
+
+	name := fieldName{}
+	if len(st.prefixes) > 0 {
+		name = st.prefixes[0]
+	}
+
+	err := readStruct(elem, &context{
+		config:           cinfo,
+		state:            st,
+		name:             name,
+		optional:         opts.AllOptional,
+		allowUnexported:  opts.AllowUnexported,
+		parsers:          opts.Parsers,
+		names:            opts.NameMapper,
+		path:             name,
+		prefix:           name,
+		customNames:      opts.CustomNames,
+		valuesAsDefaults: opts.ValuesAsDefaults,
+		leaveNil:         opts.LeaveNil,
 	})
+	if err == nil {
+		err = cinfo.resolveRelations(name)
+	}
+	return cinfo, err
 }
 
 type tag struct {
-	customName string
-	optional   bool
-	skip       bool
-	defaultVal string
-	note       string
+	customName      string
+	optional        bool
+	skip            bool
+	defaultVal      string
+	profileDefaults map[string]string
+	note            string
+	layout          string
+	tz              string
+	base            int
+	unit            string
+	encoding        string
+	params          map[string]string
+	rules           []*rule
+	relations       []*Relation
+	gate            string
+	prefix          string
+	inline          bool
+	aliases         []string
+	deprecated      bool
+	deprecation     string
+	secret          bool
+	reloadable      bool
 }
 
-func parseTag(s string) *tag {
-	var t tag
+// requiredIf reports whether the tag has a required_if= option, which makes the field optional unless its condition holds.
+func (t *tag) requiredIf() bool {
+	for _, r := range t.relations {
+		if r.Kind == "required_if" {
+			return true
+		}
+	}
+	return false
+}
 
+func parseTag(s string) (*tag, error) {
+	t := tag{base: 10}
+
+	// a token starting with an escaped character is a custom name, even if it is spelled like an option
 	escape := false
 	tokens := []string{""}
+	literal := []bool{false}
 	for _, r := range s {
 		if !escape {
 			switch r {
@@ -152,14 +385,45 @@ func parseTag(s string) *tag {
 				continue
 			case ',':
 				tokens = append(tokens, "")
+				literal = append(literal, false)
 				continue
 			}
 		}
+		if escape && tokens[len(tokens)-1] == "" {
+			literal[len(literal)-1] = true
+		}
 		escape = false
 		tokens[len(tokens)-1] += string(r)
 	}
 
-	for _, v := range tokens {
+	for i, v := range tokens {
+		if literal[i] {
+			t.customName = v
+			continue
+		}
+
+		// options taking an argument, such as min=1, are only recognised with it, so that a bare min is a custom name
+		name, arg, hasArg := v, "", false
+		if i := strings.Index(v, "="); i > 0 {
+			name, arg, hasArg = v[:i], v[i+1:], true
+			if t.params == nil {
+				t.params = make(map[string]string)
+			}
+			t.params[name] = arg
+		}
+		if isRule(name) && (hasArg || !ruleTakesArg(name)) {
+			t.rules = append(t.rules, &rule{name: name, arg: arg})
+			continue
+		}
+		if isRelation(name) && hasArg {
+			relations, err := parseRelations(name, arg)
+			if err != nil {
+				return nil, err
+			}
+			t.relations = append(t.relations, relations...)
+			continue
+		}
+
 		switch {
 		case v == "-":
 			t.skip = true
@@ -167,22 +431,178 @@ func parseTag(s string) *tag {
 			t.optional = true
 		case strings.HasPrefix(v, "default="):
 			t.defaultVal = strings.TrimPrefix(v, "default=")
+		case strings.HasPrefix(v, "default.") && strings.Contains(v, "="):
+			i := strings.Index(v, "=")
+			if t.profileDefaults == nil {
+				t.profileDefaults = make(map[string]string)
+			}
+			t.profileDefaults[v[len("default."):i]] = v[i+1:]
 		case strings.HasPrefix(v, "note="):
 			t.note = strings.TrimPrefix(v, "note=")
+		case strings.HasPrefix(v, "layout="):
+			t.layout = strings.TrimPrefix(v, "layout=")
+		case strings.HasPrefix(v, "tz="):
+			t.tz = strings.TrimPrefix(v, "tz=")
+		case v == "reloadable":
+			t.reloadable = true
+		case v == "secret":
+			t.secret = true
+		case v == "inline":
+			t.inline = true
+		case strings.HasPrefix(v, "prefix="):
+			t.prefix = strings.TrimPrefix(v, "prefix=")
+			if t.prefix == "" {
+				return nil, fmt.Errorf("envconfig: empty prefix in tag option %q, use inline instead", v)
+			}
+		case strings.HasPrefix(v, "alias="):
+			t.aliases = append(t.aliases, strings.Split(strings.TrimPrefix(v, "alias="), "|")...)
+		case v == "deprecated":
+			t.deprecated = true
+		case strings.HasPrefix(v, "deprecated="):
+			t.deprecated = true
+			t.deprecation = strings.TrimPrefix(v, "deprecated=")
+		case strings.HasPrefix(v, "gate="):
+			t.gate = strings.TrimPrefix(v, "gate=")
+		case strings.HasPrefix(v, "unit="):
+			t.unit = strings.TrimPrefix(v, "unit=")
+		case strings.HasPrefix(v, "encoding="):
+			t.encoding = strings.TrimPrefix(v, "encoding=")
+			if _, err := decodeBytes("", t.encoding); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(v, "base="):
+			base, err := strconv.Atoi(strings.TrimPrefix(v, "base="))
+			if err != nil || base == 1 || base < 0 || base > 36 {
+				return nil, fmt.Errorf("envconfig: invalid integer base in tag option %q", v)
+			}
+			t.base = base
+		case strings.Contains(v, "="):
+			// other parameters are only available through Field.Param
 		default:
 			t.customName = v
 		}
 	}
 
-	return &t
+	return &t, nil
+}
+
+// newField returns the field named name of the struct read with ctx.
+func newField(name string, value reflect.Value, tag *tag, ctx *context) (*Field, error) {
+	fld := &Field{
+		name:            ctx.name.Append(name),
+		path:            ctx.path.Append(name),
+		value:           value,
+		customName:      tag.customName,
+		defaultVal:      tag.defaultVal,
+		profileDefaults: tag.profileDefaults,
+		note:            tag.note,
+		layout:          tag.layout,
+		tz:              tag.tz,
+		base:            tag.base,
+		unit:            tag.unit,
+		encoding:        tag.encoding,
+		params:          tag.params,
+		optional:        ctx.optional || tag.optional || tag.requiredIf(),
+		allowUnexported: ctx.allowUnexported,
+		parsers:         ctx.parsers,
+		gate:            ctx.gate,
+		rules:           tag.rules,
+		relations:       tag.relations,
+		aliases:         tag.aliases,
+		deprecated:      tag.deprecated,
+		deprecation:     tag.deprecation,
+		secret:          tag.secret,
+		reloadable:      tag.reloadable,
+		names:           ctx.names,
+		state:           ctx.state,
+	}
+
+	switch {
+	case fld.customName == "" || ctx.customNames == ExactCustomNames:
+	case ctx.customNames == PrefixedCustomNames:
+		fld.path, fld.customName = ctx.prefix.Append(fld.customName), ""
+	case ctx.customNames == RelativeCustomNames:
+		fld.path, fld.customName = ctx.path.Append(fld.customName), ""
+	}
+
+	for g := ctx.gate; g != nil; g = g.parent {
+		if g.slot != nil {
+			g.slot.fields = append(g.slot.fields, fld)
+		}
+	}
+
+	if ctx.valuesAsDefaults && !value.IsZero() {
+		fld.initial = reflect.New(value.Type()).Elem()
+		fld.initial.Set(value)
+	}
+
+	if err := fld.checkDefault(); err != nil {
+		return fld, err
+	}
+	if err := fld.checkUnit(fld.value.Type()); err != nil {
+		return fld, err
+	}
+	return fld, fld.compileRules()
+}
+
+// readGate adds the field named ctx.gateOn of the struct value, which must be a bool,
+// and returns its index along with a context for the other fields of the struct, which are read only while it is true.
+func readGate(value reflect.Value, ctx *context) (int, *context, error) {
+	sf, ok := value.Type().FieldByName(ctx.gateOn)
+	if !ok || len(sf.Index) != 1 {
+		return 0, nil, fmt.Errorf("envconfig: %s: gate field %s not found", ctx.name, ctx.gateOn)
+	}
+	if sf.Type.Kind() != reflect.Bool {
+		return 0, nil, fmt.Errorf("envconfig: %s: gate field %s must be a bool", ctx.name, ctx.gateOn)
+	}
+
+	tag, err := parseTag(sf.Tag.Get("envconfig"))
+	if err != nil {
+		return 0, nil, err
+	}
+	tag.optional = true
+
+	fld, err := newField(sf.Name, value.Field(sf.Index[0]), tag, ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	ctx.config.append(fld)
+
+	sub := *ctx
+	sub.gateOn = ""
+	sub.gate = &gate{parent: ctx.gate, field: fld, value: "true"}
+	return sf.Index[0], &sub, nil
 }
 
 func readStruct(value reflect.Value, ctx *context) (err error) {
+	gateIndex := -1
+	if ctx.gateOn != "" {
+		if gateIndex, ctx, err = readGate(value, ctx); err != nil {
+			return err
+		}
+	}
+
+	ctx.state.nodes = append(ctx.state.nodes, &node{
+		name:  ctx.name,
+		value: value,
+		gate:  ctx.gate,
+		depth: ctx.depth,
+	})
+
 	for i := 0; i < value.NumField(); i++ {
+		if i == gateIndex {
+			continue
+		}
+
+		fctx := ctx
+
 		field := value.Field(i)
 		name := value.Type().Field(i).Name
 
-		tag := parseTag(value.Type().Field(i).Tag.Get("envconfig"))
+		tag, err := parseTag(value.Type().Field(i).Tag.Get("envconfig"))
+		if err != nil {
+			return err
+		}
 		if tag.skip || !field.CanSet() {
 			if !field.CanSet() && !ctx.allowUnexported {
 				return ErrUnexportedField
@@ -191,31 +611,27 @@ func readStruct(value reflect.Value, ctx *context) (err error) {
 		}
 
 	doRead:
-		switch field.Kind() {
-		case reflect.Ptr:
+		switch {
+		case field.Kind() == reflect.Ptr && !hasParser(ctx.parsers, field.Type()):
 			// it's a pointer, create a new value and restart the switch
+			if field.IsNil() && ctx.leaveNil {
+				field, fctx = readSlot(field, fctx.name.Append(name), fctx)
+				goto doRead
+			}
 			if field.IsNil() {
 				field.Set(reflect.New(field.Type().Elem()))
 			}
 			field = field.Elem()
 			goto doRead
-		case reflect.Struct:
-			err = readStruct(field, &context{
-				config:          ctx.config,
-				name:            ctx.name.Append(name),
-				optional:        ctx.optional || tag.optional,
-				allowUnexported: ctx.allowUnexported,
-			})
+		case field.Kind() == reflect.Struct && !isTimeField(field.Type()) && !hasParser(ctx.parsers, field.Type()):
+			err = readStruct(field, fctx.sub(name, tag))
+		case field.Kind() == reflect.Interface && hasVariants(field.Type()):
+			err = readVariants(field, name, tag, fctx)
 		default:
-			ctx.config.append(&Field{
-				name:            ctx.name.Append(name),
-				value:           field,
-				customName:      tag.customName,
-				defaultVal:      tag.defaultVal,
-				note:            tag.note,
-				optional:        ctx.optional || tag.optional,
-				allowUnexported: ctx.allowUnexported,
-			})
+			var fld *Field
+			if fld, err = newField(name, field, tag, fctx); err == nil {
+				ctx.config.append(fld)
+			}
 		}
 
 		if err != nil {
@@ -227,25 +643,55 @@ func readStruct(value reflect.Value, ctx *context) (err error) {
 }
 
 var (
-	durationType    = reflect.TypeOf(new(time.Duration)).Elem()
-	unmarshalerType = reflect.TypeOf(new(Unmarshaler)).Elem()
+	durationType         = reflect.TypeOf(new(time.Duration)).Elem()
+	timeType             = reflect.TypeOf(new(time.Time)).Elem()
+	unmarshalerType      = reflect.TypeOf(new(Unmarshaler)).Elem()
+	fieldUnmarshalerType = reflect.TypeOf(new(FieldUnmarshaler)).Elem()
 )
 
 func isDurationField(t reflect.Type) bool {
 	return t.AssignableTo(durationType)
 }
 
+func isTimeField(t reflect.Type) bool {
+	return t.AssignableTo(timeType)
+}
+
+func hasParser(parsers map[reflect.Type]ParseFunc, t reflect.Type) bool {
+	_, ok := parsers[t]
+	return ok
+}
+
+func parseWithParser(v reflect.Value, str string, parser ParseFunc) error {
+	val, err := parser(str)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() || !rv.Type().AssignableTo(v.Type()) {
+		return fmt.Errorf("envconfig: parser for %v returned %T", v.Type(), val)
+	}
+	v.Set(rv)
+
+	return nil
+}
+
 func isUnmarshaler(t reflect.Type) bool {
-	return t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)
+	return t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType) ||
+		t.Implements(fieldUnmarshalerType) || reflect.PtrTo(t).Implements(fieldUnmarshalerType)
 }
 
-func parseWithUnmarshaler(v reflect.Value, str string) error {
+func parseWithUnmarshaler(fld *Field, v reflect.Value, str string) error {
+	if u, ok := v.Addr().Interface().(FieldUnmarshaler); ok {
+		return u.UnmarshalField(fld, str)
+	}
 	var u = v.Addr().Interface().(Unmarshaler)
 	return u.Unmarshal(str)
 }
 
 func parseDuration(v reflect.Value, str string) error {
-	d, err := time.ParseDuration(str)
+	d, err := parseLongDuration(str)
 	if err != nil {
 		return err
 	}
@@ -255,6 +701,77 @@ func parseDuration(v reflect.Value, str string) error {
 	return nil
 }
 
+// timeLayouts maps the lower-cased names accepted by the layout= tag option to Go time layouts.
+var timeLayouts = map[string]string{
+	"ansic":       time.ANSIC,
+	"unixdate":    time.UnixDate,
+	"rubydate":    time.RubyDate,
+	"rfc822":      time.RFC822,
+	"rfc822z":     time.RFC822Z,
+	"rfc850":      time.RFC850,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+	"stampmilli":  time.StampMilli,
+	"stampmicro":  time.StampMicro,
+	"stampnano":   time.StampNano,
+	"datetime":    "2006-01-02 15:04:05",
+	"dateonly":    "2006-01-02",
+	"timeonly":    "15:04:05",
+}
+
+// parseTime parses str using layout, which is either one of the names in timeLayouts,
+// one of unix, unixmilli, unixmicro or unixnano for integer timestamps, or a Go time layout.
+// An empty layout defaults to RFC 3339.
+// If tz is not empty, times without an explicit zone are interpreted in that location.
+func parseTime(v reflect.Value, str, layout, tz string) error {
+	loc := time.UTC
+	if tz != "" {
+		var err error
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return err
+		}
+	}
+
+	var t time.Time
+	switch name := strings.ToLower(layout); name {
+	case "unix", "unixmilli", "unixmicro", "unixnano":
+		n, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "unix":
+			t = time.Unix(n, 0)
+		case "unixmilli":
+			t = time.Unix(n/1e3, (n%1e3)*1e6)
+		case "unixmicro":
+			t = time.Unix(n/1e6, (n%1e6)*1e3)
+		case "unixnano":
+			t = time.Unix(0, n)
+		}
+		t = t.In(loc)
+	default:
+		if l, ok := timeLayouts[name]; ok {
+			layout = l
+		} else if layout == "" {
+			layout = time.RFC3339
+		}
+
+		var err error
+		if t, err = time.ParseInLocation(layout, str, loc); err != nil {
+			return err
+		}
+	}
+
+	v.Set(reflect.ValueOf(t).Convert(v.Type()))
+
+	return nil
+}
+
 func parseBoolValue(v reflect.Value, str string) error {
 	val, err := strconv.ParseBool(str)
 	if err != nil {
@@ -265,8 +782,8 @@ func parseBoolValue(v reflect.Value, str string) error {
 	return nil
 }
 
-func parseIntValue(v reflect.Value, str string) error {
-	val, err := strconv.ParseInt(str, 10, 64)
+func parseIntValue(v reflect.Value, str string, base int) error {
+	val, err := strconv.ParseInt(str, base, v.Type().Bits())
 	if err != nil {
 		return err
 	}
@@ -275,8 +792,8 @@ func parseIntValue(v reflect.Value, str string) error {
 	return nil
 }
 
-func parseUintValue(v reflect.Value, str string) error {
-	val, err := strconv.ParseUint(str, 10, 64)
+func parseUintValue(v reflect.Value, str string, base int) error {
+	val, err := strconv.ParseUint(str, base, v.Type().Bits())
 	if err != nil {
 		return err
 	}
@@ -286,7 +803,7 @@ func parseUintValue(v reflect.Value, str string) error {
 }
 
 func parseFloatValue(v reflect.Value, str string) error {
-	val, err := strconv.ParseFloat(str, 64)
+	val, err := strconv.ParseFloat(str, v.Type().Bits())
 	if err != nil {
 		return err
 	}
@@ -295,8 +812,27 @@ func parseFloatValue(v reflect.Value, str string) error {
 	return nil
 }
 
-func parseBytesValue(v reflect.Value, str string) error {
-	val, err := base64.StdEncoding.DecodeString(str)
+// decodeBytes decodes str using one of the encodings accepted by the encoding= tag option.
+// An empty encoding is standard base64.
+func decodeBytes(str, encoding string) ([]byte, error) {
+	switch encoding {
+	case "", "base64":
+		return base64.StdEncoding.DecodeString(str)
+	case "base64url":
+		return base64.URLEncoding.DecodeString(str)
+	case "rawbase64":
+		return base64.RawStdEncoding.DecodeString(str)
+	case "hex":
+		return hex.DecodeString(str)
+	case "raw":
+		return []byte(str), nil
+	default:
+		return nil, fmt.Errorf("envconfig: unknown encoding %q", encoding)
+	}
+}
+
+func parseBytesValue(v reflect.Value, str, encoding string) error {
+	val, err := decodeBytes(str, encoding)
 	if err != nil {
 		return err
 	}
@@ -304,3 +840,16 @@ func parseBytesValue(v reflect.Value, str string) error {
 
 	return nil
 }
+
+func parseByteArrayValue(v reflect.Value, str, encoding string) error {
+	val, err := decodeBytes(str, encoding)
+	if err != nil {
+		return err
+	}
+	if len(val) != v.Len() {
+		return fmt.Errorf("envconfig: decoded value is %d bytes long but %v needs %d", len(val), v.Type(), v.Len())
+	}
+	reflect.Copy(v, reflect.ValueOf(val))
+
+	return nil
+}
@@ -1,8 +1,13 @@
 package envconfig_test
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -60,6 +65,66 @@ func TestParseIntegerConfig(t *testing.T) {
 	require.Equal(t, uint8(2), conf.Version)
 }
 
+func TestParseIntegerOverflow(t *testing.T) {
+	var conf struct{ Small int8 }
+	os.Setenv("SMALL", "300")
+	err := envconfig.Init(&conf)
+	require.Equal(t, `strconv.ParseInt: parsing "300": value out of range`, err.Error())
+
+	var conf2 struct{ Small uint16 }
+	os.Setenv("SMALL", "65536")
+	err = envconfig.Init(&conf2)
+	require.Equal(t, `strconv.ParseUint: parsing "65536": value out of range`, err.Error())
+
+	var conf3 struct{ Small float32 }
+	os.Setenv("SMALL", "1e40")
+	err = envconfig.Init(&conf3)
+	require.Equal(t, `strconv.ParseFloat: parsing "1e40": value out of range`, err.Error())
+
+	var conf4 struct{ Small int8 }
+	os.Setenv("SMALL", "-128")
+	err = envconfig.Init(&conf4)
+	require.Nil(t, err)
+	require.Equal(t, int8(-128), conf4.Small)
+}
+
+func TestParseIntegerBase(t *testing.T) {
+	var conf struct {
+		Mask   uint32   `envconfig:"base=0"`
+		Limit  int      `envconfig:"base=0"`
+		Mode   int      `envconfig:"base=8"`
+		Flags  []uint16 `envconfig:"base=0"`
+		Plain  int
+		Signed int16 `envconfig:"base=0"`
+	}
+
+	os.Setenv("MASK", "0xff_ff")
+	os.Setenv("LIMIT", "1_000_000")
+	os.Setenv("MODE", "755")
+	os.Setenv("FLAGS", "0b101,0o17,0x0f")
+	os.Setenv("PLAIN", "010")
+	os.Setenv("SIGNED", "-0x8000")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, uint32(0xffff), conf.Mask)
+	require.Equal(t, 1000000, conf.Limit)
+	require.Equal(t, 0755, conf.Mode)
+	require.Equal(t, []uint16{5, 15, 15}, conf.Flags)
+	require.Equal(t, 10, conf.Plain)
+	require.Equal(t, int16(-0x8000), conf.Signed)
+
+	os.Setenv("PLAIN", "0x10")
+	err = envconfig.Init(&conf)
+	require.Equal(t, `strconv.ParseInt: parsing "0x10": invalid syntax`, err.Error())
+
+	var conf2 struct {
+		Mask uint32 `envconfig:"base=x"`
+	}
+	err = envconfig.Init(&conf2)
+	require.Equal(t, `envconfig: invalid integer base in tag option "base=x"`, err.Error())
+}
+
 func TestParseBoolConfig(t *testing.T) {
 	var conf struct {
 		DoIt bool
@@ -84,6 +149,42 @@ func TestParseBytesConfig(t *testing.T) {
 	require.Equal(t, []byte("FOOBAR"), conf.Data)
 }
 
+func TestParseBytesEncodings(t *testing.T) {
+	var conf struct {
+		HMACKey   []byte   `envconfig:"encoding=hex"`
+		JWTSecret []byte   `envconfig:"encoding=base64url"`
+		Token     []byte   `envconfig:"encoding=rawbase64"`
+		Greeting  []byte   `envconfig:"encoding=raw"`
+		Salt      [4]byte  `envconfig:"encoding=hex"`
+		Nonce     *[3]byte `envconfig:"default=Rk9P"`
+	}
+
+	os.Setenv("HMACKEY", "deadbeef")
+	os.Setenv("JWTSECRET", "-_-_")
+	os.Setenv("TOKEN", "Rk9PQg")
+	os.Setenv("GREETING", "hello")
+	os.Setenv("SALT", "00010203")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, conf.HMACKey)
+	require.Equal(t, []byte{0xfb, 0xff, 0xbf}, conf.JWTSecret)
+	require.Equal(t, []byte("FOOB"), conf.Token)
+	require.Equal(t, []byte("hello"), conf.Greeting)
+	require.Equal(t, [4]byte{0, 1, 2, 3}, conf.Salt)
+	require.Equal(t, [3]byte{'F', 'O', 'O'}, *conf.Nonce)
+
+	os.Setenv("SALT", "0001")
+	err = envconfig.Init(&conf)
+	require.Equal(t, "envconfig: decoded value is 2 bytes long but [4]uint8 needs 4", err.Error())
+
+	var conf2 struct {
+		Key []byte `envconfig:"encoding=base32"`
+	}
+	err = envconfig.Init(&conf2)
+	require.Equal(t, `envconfig: unknown encoding "base32"`, err.Error())
+}
+
 func TestParseFloatConfig(t *testing.T) {
 	var conf struct {
 		Delta  float32
@@ -208,6 +309,114 @@ func TestInvalidDurationConfig(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestTimeConfig(t *testing.T) {
+	var conf struct {
+		Cutover   time.Time
+		Start     time.Time   `envconfig:"layout=DateOnly"`
+		Window    time.Time   `envconfig:"layout=2006-01-02 15:04,tz=Europe/Paris"`
+		Epoch     time.Time   `envconfig:"layout=unix"`
+		EpochMs   *time.Time  `envconfig:"layout=unixmilli"`
+		Holidays  []time.Time `envconfig:"layout=dateonly"`
+		Announced time.Time   `envconfig:"layout=RFC1123,default=Mon\\, 02 Jan 2006 15:04:05 MST"`
+	}
+
+	os.Setenv("CUTOVER", "2024-03-01T12:30:00+02:00")
+	os.Setenv("START", "2024-03-01")
+	os.Setenv("WINDOW", "2024-03-01 02:00")
+	os.Setenv("EPOCH", "1700000000")
+	os.Setenv("EPOCHMS", "1700000000123")
+	os.Setenv("HOLIDAYS", "2024-12-25,2024-12-26")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.Nil(t, err)
+
+	require.True(t, conf.Cutover.Equal(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)))
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), conf.Start)
+	require.True(t, conf.Window.Equal(time.Date(2024, 3, 1, 2, 0, 0, 0, paris)))
+	require.Equal(t, "Europe/Paris", conf.Window.Location().String())
+	require.Equal(t, int64(1700000000), conf.Epoch.Unix())
+	require.Equal(t, int64(1700000000123), conf.EpochMs.UnixNano()/int64(time.Millisecond))
+	require.Equal(t, 2, len(conf.Holidays))
+	require.Equal(t, time.December, conf.Holidays[1].Month())
+	require.Equal(t, 26, conf.Holidays[1].Day())
+	require.Equal(t, 2006, conf.Announced.Year())
+}
+
+func TestInvalidTimeConfig(t *testing.T) {
+	var conf struct {
+		Cutover time.Time
+	}
+
+	os.Setenv("CUTOVER", "2024-03-01")
+
+	err := envconfig.Init(&conf)
+	require.NotNil(t, err)
+
+	var conf2 struct {
+		Window time.Time `envconfig:"tz=Nowhere/Special"`
+	}
+
+	os.Setenv("WINDOW", "2024-03-01T12:30:00Z")
+
+	err = envconfig.Init(&conf2)
+	require.Equal(t, "unknown time zone Nowhere/Special", err.Error())
+}
+
+func TestLongDurationConfig(t *testing.T) {
+	var conf struct {
+		Retention time.Duration
+	}
+
+	os.Setenv("RETENTION", "2w3d")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, 17*24*time.Hour, conf.Retention)
+}
+
+func TestByteSizeConfig(t *testing.T) {
+	var conf struct {
+		CacheSize  envconfig.ByteSize
+		BufferSize int    `envconfig:"unit=bytes"`
+		Limits     []uint `envconfig:"unit=bytes"`
+		MaxUpload  uint64 `envconfig:"unit=bytes,default=1.5GB"`
+	}
+
+	os.Setenv("CACHE_SIZE", "512MiB")
+	os.Setenv("BUFFER_SIZE", "64KiB")
+	os.Setenv("LIMITS", "1kB,2KiB")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, envconfig.ByteSize(512<<20), conf.CacheSize)
+	require.Equal(t, "512MiB", conf.CacheSize.String())
+	require.Equal(t, 64<<10, conf.BufferSize)
+	require.Equal(t, []uint{1000, 2048}, conf.Limits)
+	require.Equal(t, uint64(1500000000), conf.MaxUpload)
+
+	var conf2 struct {
+		Small uint8 `envconfig:"unit=bytes"`
+	}
+	os.Setenv("SMALL", "1KiB")
+	err = envconfig.Init(&conf2)
+	require.Equal(t, `envconfig: byte size "1KiB" overflows uint8`, err.Error())
+
+	var conf3 struct {
+		Small uint8 `envconfig:"unit=furlongs"`
+	}
+	_, err = envconfig.Parse(&conf3)
+	require.Equal(t, `envconfig: unknown unit "furlongs"`, err.Error())
+
+	var conf4 struct {
+		Limits map[string]*int `envconfig:"unit=byte"`
+	}
+	_, err = envconfig.Parse(&conf4)
+	require.Equal(t, `envconfig: unknown unit "byte"`, err.Error())
+}
+
 func TestAllPointerConfig(t *testing.T) {
 	var conf struct {
 		Name   *string
@@ -296,6 +505,58 @@ func TestUnmarshaler(t *testing.T) {
 	require.Equal(t, logFile, *conf2.LogMode)
 }
 
+type interval time.Duration
+
+func (i *interval) UnmarshalField(fld *envconfig.Field, s string) error {
+	unit, ok := fld.Param("unit")
+	if !ok {
+		return fmt.Errorf("%s: missing unit", fld.Name())
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s: %s is not a number of %s", fld.Name(), s, unit)
+	}
+
+	switch unit {
+	case "ms":
+		*i = interval(time.Duration(n) * time.Millisecond)
+	case "s":
+		*i = interval(time.Duration(n) * time.Second)
+	default:
+		return fmt.Errorf("%s: unknown unit %s", fld.Name(), unit)
+	}
+
+	return nil
+}
+
+func TestFieldUnmarshaler(t *testing.T) {
+	var conf struct {
+		Poll     interval   `envconfig:"unit=ms"`
+		Backoff  *interval  `envconfig:"unit=s,default=2"`
+		Retries  []interval `envconfig:"unit=s"`
+		Fallback interval   `envconfig:"unit=h,optional"`
+	}
+
+	os.Setenv("POLL", "250")
+	os.Setenv("RETRIES", "1,5")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, interval(250*time.Millisecond), conf.Poll)
+	require.Equal(t, interval(2*time.Second), *conf.Backoff)
+	require.Equal(t, []interval{interval(time.Second), interval(5 * time.Second)}, conf.Retries)
+
+	os.Setenv("FALLBACK", "1")
+	err = envconfig.Init(&conf)
+	require.Equal(t, "Fallback: unknown unit h", err.Error())
+
+	os.Setenv("FALLBACK", "")
+	os.Setenv("POLL", "soon")
+	err = envconfig.Init(&conf)
+	require.Equal(t, "Poll: soon is not a number of ms", err.Error())
+}
+
 func TestParseOptionalConfig(t *testing.T) {
 	var conf struct {
 		Name    string        `envconfig:"optional"`
@@ -363,6 +624,50 @@ func TestParseCustomNameConfig(t *testing.T) {
 	require.Equal(t, "foobar", conf.Name)
 }
 
+func TestParseCustomNameLikeOption(t *testing.T) {
+	var conf struct {
+		ListenPort string `envconfig:"\\port"`
+		Inline     string `envconfig:"\\inline,optional"`
+		Token      string `envconfig:"secret,TOKEN_FILE"`
+	}
+
+	os.Setenv("port", "8080")
+	os.Setenv("inline", "yes")
+	os.Setenv("TOKEN_FILE", "/run/secrets/token")
+	defer func() {
+		os.Setenv("port", "")
+		os.Setenv("inline", "")
+		os.Setenv("TOKEN_FILE", "")
+	}()
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, "8080", conf.ListenPort)
+	require.Equal(t, "yes", conf.Inline)
+	require.Equal(t, "/run/secrets/token", conf.Token)
+	require.Equal(t, true, (*cinfo)[1].Optional())
+	require.Equal(t, true, (*cinfo)[2].Secret())
+
+	var conf2 struct {
+		ListenPort string `envconfig:"port"`
+	}
+
+	cinfo, err = envconfig.Parse(&conf2)
+	require.Nil(t, err)
+	require.Equal(t, []string{"LISTENPORT", "LISTEN_PORT", "listen_port", "listenport"}, (*cinfo)[0].Keys())
+
+	var conf3 struct {
+		Min       string `envconfig:"min"`
+		Conflicts string `envconfig:"conflicts,optional"`
+	}
+
+	cinfo, err = envconfig.Parse(&conf3)
+	require.Nil(t, err)
+	require.Equal(t, []string{"min"}, (*cinfo)[0].Keys())
+	require.Equal(t, []string{"conflicts"}, (*cinfo)[1].Keys())
+}
+
 func TestParseOptionalStruct(t *testing.T) {
 	var conf struct {
 		Master struct {
@@ -573,3 +878,412 @@ func TestParseMapType(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, 1, conf.Map["a"])
 }
+
+func TestParseMapConfig(t *testing.T) {
+	var conf struct {
+		Weights map[string]int
+		Peers   map[string]string
+	}
+
+	os.Setenv("WEIGHTS", "a:1,b:2")
+	os.Setenv("PEERS", "a:localhost:2828,b:localhost:2929")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, conf.Weights)
+	require.Equal(t, map[string]string{"a": "localhost:2828", "b": "localhost:2929"}, conf.Peers)
+
+	os.Setenv("WEIGHTS", "a")
+	err = envconfig.Init(&conf)
+	require.Equal(t, `envconfig: map token "a" is not a key:value pair`, err.Error())
+}
+
+func TestParsers(t *testing.T) {
+	parseURL := func(s string) (interface{}, error) {
+		return url.Parse(s)
+	}
+	parseOnOff := func(s string) (interface{}, error) {
+		switch s {
+		case "on":
+			return true, nil
+		case "off":
+			return false, nil
+		}
+		return nil, errors.New("expected on or off")
+	}
+
+	var conf struct {
+		Endpoint *url.URL
+		Mirrors  []*url.URL
+		Upstream map[string]*url.URL
+		Verbose  bool
+		Debug    *bool
+	}
+
+	os.Setenv("ENDPOINT", "https://example.com/api")
+	os.Setenv("MIRRORS", "https://a.example.com,https://b.example.com")
+	os.Setenv("UPSTREAM", "eu:https://eu.example.com,us:https://us.example.com")
+	os.Setenv("VERBOSE", "on")
+	os.Setenv("DEBUG", "off")
+
+	err := envconfig.InitWithOptions(&conf, envconfig.Options{
+		Parsers: map[reflect.Type]envconfig.ParseFunc{
+			reflect.TypeOf((*url.URL)(nil)): parseURL,
+			reflect.TypeOf(true):            parseOnOff,
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "example.com", conf.Endpoint.Host)
+	require.Equal(t, 2, len(conf.Mirrors))
+	require.Equal(t, "b.example.com", conf.Mirrors[1].Host)
+	require.Equal(t, "us.example.com", conf.Upstream["us"].Host)
+	require.Equal(t, true, conf.Verbose)
+	require.Equal(t, false, *conf.Debug)
+
+	os.Setenv("VERBOSE", "true")
+	err = envconfig.InitWithOptions(&conf, envconfig.Options{
+		Parsers: map[reflect.Type]envconfig.ParseFunc{
+			reflect.TypeOf((*url.URL)(nil)): parseURL,
+			reflect.TypeOf(true):            parseOnOff,
+		},
+	})
+	require.Equal(t, "expected on or off", err.Error())
+
+	var conf2 struct {
+		Endpoint url.URL
+	}
+	err = envconfig.InitWithOptions(&conf2, envconfig.Options{
+		Parsers: map[reflect.Type]envconfig.ParseFunc{
+			reflect.TypeOf(url.URL{}): parseURL,
+		},
+	})
+	require.Equal(t, "envconfig: parser for url.URL returned *url.URL", err.Error())
+}
+
+type metricsConfig struct {
+	Addr    string
+	Enabled bool
+	Labels  []string `envconfig:"optional"`
+}
+
+func (c *metricsConfig) Validate() error {
+	if c.Addr == "invalid" {
+		return errors.New("invalid address")
+	}
+	return nil
+}
+
+func TestGatedStruct(t *testing.T) {
+	var conf struct {
+		Name    string        `envconfig:"default=app"`
+		Metrics metricsConfig `envconfig:"gate=Enabled"`
+	}
+
+	os.Setenv("METRICS_ENABLED", "")
+	os.Setenv("METRICS_ADDR", "invalid")
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Equal(t, "Metrics.Enabled", (*cinfo)[1].Name())
+	require.Equal(t, true, (*cinfo)[1].Optional())
+	require.Equal(t, "", (*cinfo)[1].Condition())
+	require.Equal(t, "METRICS_ENABLED=true", (*cinfo)[2].Condition())
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "", conf.Metrics.Addr)
+
+	os.Setenv("METRICS_ENABLED", "true")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: Metrics: invalid address", err.Error())
+
+	os.Setenv("METRICS_ADDR", "")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: keys METRICS_ADDR, metrics_addr not found", err.Error())
+
+	os.Setenv("METRICS_ADDR", ":9090")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, ":9090", conf.Metrics.Addr)
+
+	os.Setenv("METRICS_ENABLED", "")
+	os.Setenv("METRICS_ADDR", "")
+}
+
+func TestInvalidGate(t *testing.T) {
+	var conf struct {
+		Metrics struct {
+			Addr string
+		} `envconfig:"gate=Enabled"`
+	}
+
+	_, err := envconfig.Parse(&conf)
+	require.Equal(t, "envconfig: Metrics: gate field Enabled not found", err.Error())
+
+	var conf2 struct {
+		Metrics struct {
+			Enabled string
+		} `envconfig:"gate=Enabled"`
+	}
+
+	_, err = envconfig.Parse(&conf2)
+	require.Equal(t, "envconfig: Metrics: gate field Enabled must be a bool", err.Error())
+}
+
+func TestAliases(t *testing.T) {
+	var conf struct {
+		Name  string `envconfig:"NEW_NAME,alias=OLD_NAME|OLDER_NAME"`
+		Trace bool   `envconfig:"optional,deprecated=use LOG_LEVEL"`
+	}
+
+	var warnings []string
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{
+		Warn: func(fld *envconfig.Field, msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []string{"OLD_NAME", "OLDER_NAME"}, (*cinfo)[0].Aliases())
+
+	msg, ok := (*cinfo)[1].Deprecated()
+	require.True(t, ok)
+	require.Equal(t, "use LOG_LEVEL", msg)
+
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: keys NEW_NAME, OLD_NAME, OLDER_NAME not found", err.Error())
+
+	os.Setenv("OLDER_NAME", "foo")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "foo", conf.Name)
+	require.Equal(t, []string{"envconfig: OLDER_NAME is deprecated: use NEW_NAME"}, warnings)
+
+	warnings = nil
+	os.Setenv("NEW_NAME", "bar")
+	os.Setenv("TRACE", "true")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "bar", conf.Name)
+	require.True(t, conf.Trace)
+	require.Equal(t, []string{"envconfig: TRACE is deprecated: use LOG_LEVEL"}, warnings)
+
+	os.Setenv("OLDER_NAME", "")
+	os.Setenv("NEW_NAME", "")
+	os.Setenv("TRACE", "")
+}
+
+func TestInterpolation(t *testing.T) {
+	var conf struct {
+		Host string `envconfig:"APP_HOST,default=localhost"`
+		Addr string `envconfig:"APP_ADDR,default=${APP_HOST}:${APP_PORT:-8080}"`
+		URL  string `envconfig:"APP_URL,optional"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "localhost:8080", conf.Addr)
+	require.Equal(t, "${APP_HOST}:${APP_PORT:-8080}", (*cinfo)[1].Default())
+
+	os.Setenv("APP_HOST", "example.com")
+	os.Setenv("APP_PORT", "9000")
+	os.Setenv("APP_URL", "http://${APP_ADDR}")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "example.com:9000", conf.Addr)
+	require.Equal(t, "http://${APP_ADDR}", conf.URL)
+
+	cinfo, err = envconfig.ParseWithOptions(&conf, envconfig.Options{Interpolate: true})
+	require.Nil(t, err)
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "http://example.com:9000", conf.URL)
+
+	os.Setenv("APP_HOST", "${APP_URL}")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: Host: reference cycle", err.Error())
+
+	os.Setenv("APP_HOST", "")
+	os.Setenv("APP_PORT", "")
+	os.Setenv("APP_URL", "")
+}
+
+func TestFileKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "envconfig")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	password := filepath.Join(dir, "password")
+	require.Nil(t, ioutil.WriteFile(password, []byte("hunter2\n"), 0600))
+
+	var conf struct {
+		User     string `envconfig:"FK_USER,optional"`
+		Password string `envconfig:"FK_PASSWORD"`
+	}
+
+	os.Setenv("FK_USER_FILE", password)
+	os.Setenv("FK_PASSWORD_FILE", password)
+	defer func() {
+		os.Setenv("FK_USER", "")
+		os.Setenv("FK_USER_FILE", "")
+		os.Setenv("FK_PASSWORD_FILE", "")
+	}()
+
+	err = envconfig.Init(&conf)
+	require.Equal(t, "envconfig: keys FK_PASSWORD not found", err.Error())
+
+	os.Setenv("FK_USER", "admin")
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{FileKeys: true})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, "admin", conf.User)
+	require.Equal(t, "hunter2", conf.Password)
+	require.Equal(t, "FK_PASSWORD", (*cinfo)[1].Key())
+	require.Equal(t, []string{password}, cinfo.Files())
+
+	os.Setenv("FK_PASSWORD_FILE", filepath.Join(dir, "missing"))
+	err = cinfo.Read()
+	require.True(t, strings.HasPrefix(err.Error(), "envconfig: FK_PASSWORD_FILE: open "), err.Error())
+}
+
+func TestValuesAsDefaults(t *testing.T) {
+	conf := struct {
+		Name    string
+		Timeout time.Duration `envconfig:"default=5s"`
+		Mask    uint32        `envconfig:"base=16"`
+		Limit   int64         `envconfig:"unit=bytes"`
+		Hosts   []string
+		Labels  map[string]int
+		Key     []byte `envconfig:"encoding=hex"`
+		Retries int    `envconfig:"default=3"`
+	}{
+		Name:    "server",
+		Timeout: time.Minute,
+		Mask:    0xff,
+		Limit:   2 << 20,
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]int{"y": 2, "x": 1},
+		Key:     []byte{0xde, 0xad},
+	}
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "Vad", ValuesAsDefaults: true})
+	require.Nil(t, err)
+
+	var defaults []string
+	for _, fld := range *cinfo {
+		defaults = append(defaults, fld.Default())
+	}
+	require.Equal(t, []string{"server", "1m0s", "ff", "2MiB", "a,b", "x:1,y:2", "dead", "3"}, defaults)
+
+	os.Setenv("VAD_NAME", "other")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "other", conf.Name)
+	require.Equal(t, time.Minute, conf.Timeout)
+	require.Equal(t, []string{"a", "b"}, conf.Hosts)
+	require.Equal(t, 3, conf.Retries)
+	require.Equal(t, "1m0s", (*cinfo)[1].Value())
+
+	os.Setenv("VAD_NAME", "")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "server", conf.Name)
+
+	os.Setenv("VAD_HOSTS", "c")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, []string{"c"}, conf.Hosts)
+	os.Setenv("VAD_HOSTS", "")
+
+	conf.Name = ""
+	err = envconfig.InitWithPrefix(&conf, "Vad")
+	require.Equal(t, "envconfig: keys VAD_NAME, vad_name not found", err.Error())
+}
+
+func TestLeaveNil(t *testing.T) {
+	type tls struct {
+		Cert string
+		Key  string
+	}
+
+	var conf struct {
+		TLS     *tls
+		Proxy   *struct{ URL string }
+		Workers *int
+		Level   *string `envconfig:"default=info"`
+	}
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "LN", LeaveNil: true})
+	require.Nil(t, err)
+	require.Nil(t, conf.TLS)
+	require.Equal(t, 5, len(*cinfo))
+	require.Equal(t, "LN.TLS is set", (*cinfo)[0].Condition())
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Nil(t, conf.TLS)
+	require.Nil(t, conf.Proxy)
+	require.Nil(t, conf.Workers)
+	require.Equal(t, "info", *conf.Level)
+
+	os.Setenv("LN_TLS_CERT", "cert.pem")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: keys LN_TLS_KEY, ln_tls_key not found", err.Error())
+
+	os.Setenv("LN_TLS_KEY", "key.pem")
+	os.Setenv("LN_WORKERS", "4")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, &tls{Cert: "cert.pem", Key: "key.pem"}, conf.TLS)
+	require.Nil(t, conf.Proxy)
+	require.Equal(t, 4, *conf.Workers)
+
+	os.Setenv("LN_TLS_CERT", "")
+	os.Setenv("LN_TLS_KEY", "")
+	os.Setenv("LN_WORKERS", "")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Nil(t, conf.TLS)
+	require.Nil(t, conf.Workers)
+}
+
+func TestProfiles(t *testing.T) {
+	var conf struct {
+		Level string `envconfig:"PRF_LEVEL,default=info,default.prod=warn,default.dev=debug"`
+		Port  int    `envconfig:"PRF_PORT,default=8080"`
+	}
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Profile: "prod"})
+	require.Nil(t, err)
+	require.Equal(t, []string{"dev", "prod"}, (*cinfo)[0].Profiles())
+	require.Equal(t, "info", (*cinfo)[0].Default())
+	require.Equal(t, "debug", (*cinfo)[0].ProfileDefault("dev"))
+	require.Equal(t, "8080", (*cinfo)[1].ProfileDefault("dev"))
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "warn", conf.Level)
+	require.Equal(t, 8080, conf.Port)
+
+	cinfo, err = envconfig.ParseWithOptions(&conf, envconfig.Options{ProfileKey: "PRF_ENV"})
+	require.Nil(t, err)
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "info", conf.Level)
+
+	os.Setenv("PRF_ENV", "dev")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "debug", conf.Level)
+
+	os.Setenv("PRF_LEVEL", "error")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "error", conf.Level)
+
+	os.Setenv("PRF_ENV", "")
+	os.Setenv("PRF_LEVEL", "")
+}
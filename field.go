@@ -3,23 +3,44 @@ package envconfig
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"reflect"
 	"sort"
 	"strings"
-	"unicode"
 )
 
 // Field represents a single field in a configuration struct.
 type Field struct {
 	name            fieldName
+	path            fieldName
 	value           reflect.Value
 	strValue        string
 	customName      string
 	defaultVal      string
+	profileDefaults map[string]string
 	note            string
+	layout          string
+	tz              string
+	base            int
+	unit            string
+	encoding        string
+	params          map[string]string
 	optional        bool
 	allowUnexported bool
+	parsers         map[reflect.Type]ParseFunc
+	gate            *gate
+	variants        []*variant
+	rules           []*rule
+	relations       []*Relation
+	aliases         []string
+	deprecated      bool
+	deprecation     string
+	secret          bool
+	reloadable      bool
+	key             string
+	initial         reflect.Value
+	provided        *providedValue
+	names           NameMapper
+	state           *state
 }
 
 // Name returns the full name of the field.
@@ -34,26 +55,177 @@ func (fld *Field) Value() string {
 }
 
 // Default returns the default value for this field.
+// A default value computed by a provider is returned as the name of the provider, for example @hostname.
+// With Options.ValuesAsDefaults, the value the field had before Parse is formatted as it would be written in a key.
 func (fld *Field) Default() string {
+	if fld.initial.IsValid() {
+		return fld.formatValue(fld.initial)
+	}
 	return fld.defaultVal
 }
 
+// Profiles returns the sorted names of the profiles this field has a default value for, set with default.<profile>= tag options.
+func (fld *Field) Profiles() []string {
+	var res []string
+	for p := range fld.profileDefaults {
+		res = append(res, p)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ProfileDefault returns the default value used for this field with the profile named profile.
+// ProfileDefault returns the same as Default if the field has no default value for the profile.
+func (fld *Field) ProfileDefault(profile string) string {
+	if v, ok := fld.profileDefaults[profile]; ok && !fld.initial.IsValid() {
+		return v
+	}
+	return fld.Default()
+}
+
 // Note returns any notes set for this field.
 func (fld *Field) Note() string {
 	return fld.note
 }
 
+// Param returns the value of the key=value option named key in the field's tag,
+// and whether the option was present.
+// Options which envconfig does not recognise are available to FieldUnmarshaler implementations this way.
+func (fld *Field) Param(key string) (string, bool) {
+	v, ok := fld.params[key]
+	return v, ok
+}
+
+// Encoding returns the encoding of the value of a []byte or [N]byte field, as set with the encoding= tag option.
+// Encoding returns an empty string for other fields.
+func (fld *Field) Encoding() string {
+	t := fld.value.Type()
+	if isUnmarshaler(t) || hasParser(fld.parsers, t) || (t != byteSliceType && !isByteArray(t)) {
+		return ""
+	}
+	if fld.encoding == "" {
+		return "base64"
+	}
+	return fld.encoding
+}
+
+// Choices returns the names of the implementations this field selects between,
+// for the discriminator field of an interface field. Choices returns nil for other fields.
+func (fld *Field) Choices() []string {
+	var names []string
+	for _, v := range fld.variants {
+		names = append(names, v.name)
+	}
+	return names
+}
+
+// Condition describes when this field is read, for example STORAGE_DRIVER=s3.
+// Condition returns an empty string for fields which are always read.
+func (fld *Field) Condition() string {
+	return fld.gate.String()
+}
+
+// Aliases returns the extra keys accepted for this field, set with the alias= tag option.
+// Aliases are tried after the keys returned by Keys.
+func (fld *Field) Aliases() []string {
+	return fld.aliases
+}
+
+// Deprecated returns the message set with the deprecated= tag option, and whether the field is deprecated.
+func (fld *Field) Deprecated() (string, bool) {
+	return fld.deprecation, fld.deprecated
+}
+
+// warnDeprecated calls Options.Warn if the value of the field was read from an alias or with a fallback prefix,
+// or the field is deprecated.
+func (fld *Field) warnDeprecated() {
+	isAlias := false
+	for _, alias := range fld.aliases {
+		isAlias = isAlias || fld.key == alias
+	}
+	isPrimary := false
+	for _, key := range fld.primaryKeys() {
+		isPrimary = isPrimary || fld.key == key
+	}
+
+	switch {
+	case fld.deprecated && fld.deprecation != "":
+		fld.state.warn(fld, fmt.Sprintf("envconfig: %s is deprecated: %s", fld.key, fld.deprecation))
+	case fld.deprecated:
+		fld.state.warn(fld, fmt.Sprintf("envconfig: %s is deprecated", fld.key))
+	case isAlias:
+		fld.state.warn(fld, fmt.Sprintf("envconfig: %s is deprecated: use %s", fld.key, fld.displayKey()))
+	case !isPrimary:
+		fld.state.warn(fld, fmt.Sprintf("envconfig: %s uses a fallback prefix: use %s", fld.key, fld.displayKey()))
+	}
+}
+
+// Secret returns whether the value of this field is secret, as set with the secret tag option.
+// The values and default values of secret fields are redacted in the changes returned by Reload and in the docs subpackage.
+func (fld *Field) Secret() bool {
+	return fld.secret
+}
+
+// Reloadable returns whether the value of this field may be changed by Reload when Options.RestrictReload is set,
+// as set with the reloadable tag option.
+func (fld *Field) Reloadable() bool {
+	return fld.reloadable
+}
+
 // Optional returns whether or not this field is optional.
 func (fld *Field) Optional() bool {
 	return fld.optional
 }
 
 // Keys returns a slice containing all environment keys that will be tried when populating this field.
+// The keys are chosen by Options.NameMapper unless the field has a custom name,
+// and are followed by the keys under each of the other Options.Prefixes.
 func (fld *Field) Keys() []string {
+	keys := fld.primaryKeys()
+	if fld.customName != "" || len(fld.state.prefixes) < 2 {
+		return keys
+	}
+
+	rest := fld.path[len(fld.state.prefixes[0]):]
+	for _, prefix := range fld.state.prefixes[1:] {
+		keys = append(keys, fld.mapKeys(append(prefix[:len(prefix):len(prefix)], rest...))...)
+	}
+	return keys
+}
+
+// Key returns the key the value of this field was read from.
+// With Options.FileKeys, a value read from the file named by the key with the _FILE suffix is reported as read from the key.
+// Key returns an empty string until Read() is called, and when the value was not read from a key.
+func (fld *Field) Key() string {
+	return fld.key
+}
+
+// primaryKeys returns the keys of the field under the first prefix.
+func (fld *Field) primaryKeys() []string {
 	if fld.customName != "" {
 		return []string{fld.customName}
 	}
-	return fld.name.Keys()
+	return fld.mapKeys(fld.path)
+}
+
+func (fld *Field) mapKeys(path fieldName) []string {
+	if fld.names == nil {
+		return path.Keys()
+	}
+	return fld.names.Keys(path)
+}
+
+// displayKey returns the key used to refer to this field in messages:
+// the longest upper case key, or the first key if none are upper case.
+func (fld *Field) displayKey() string {
+	keys := fld.primaryKeys()
+	res := keys[0]
+	for _, key := range keys {
+		if key == strings.ToUpper(key) && (res != strings.ToUpper(res) || len(key) > len(res)) {
+			res = key
+		}
+	}
+	return res
 }
 
 func (fld *Field) setValue() (err error) {
@@ -62,26 +234,42 @@ func (fld *Field) setValue() (err error) {
 
 var byteSliceType = reflect.TypeOf([]byte(nil))
 
+func isByteArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8
+}
+
 func (fld *Field) setField(value reflect.Value) (err error) {
 	str, err := fld.readValue()
 	if err != nil {
 		return err
 	}
 
+	if fld.key == "" && fld.initial.IsValid() {
+		fld.strValue = str
+		value.Set(fld.initial)
+		return nil
+	}
+
 	if len(str) == 0 && fld.optional {
 		return nil
 	}
 
 	fld.strValue = str
 
-	isSliceNotUnmarshaler := value.Kind() == reflect.Slice && !isUnmarshaler(value.Type())
+	isBuiltin := !isUnmarshaler(value.Type()) && !hasParser(fld.parsers, value.Type())
 	switch {
-	case isSliceNotUnmarshaler && value.Type() == byteSliceType:
-		return parseBytesValue(value, str)
+	case isBuiltin && value.Type() == byteSliceType:
+		return parseBytesValue(value, str, fld.encoding)
 
-	case isSliceNotUnmarshaler:
+	case isBuiltin && isByteArray(value.Type()):
+		return parseByteArrayValue(value, str, fld.encoding)
+
+	case isBuiltin && value.Kind() == reflect.Slice:
 		return fld.setSliceField(value, str)
 
+	case isBuiltin && value.Kind() == reflect.Map:
+		return fld.setMapField(value, str)
+
 	default:
 		return fld.parseValue(value, str)
 	}
@@ -91,7 +279,7 @@ func (fld *Field) setSliceField(value reflect.Value, str string) error {
 	elType := value.Type().Elem()
 	tnz := newSliceTokenizer(str)
 
-	slice := reflect.MakeSlice(value.Type(), value.Len(), value.Cap())
+	slice := reflect.MakeSlice(value.Type(), 0, value.Len())
 
 	for tnz.scan() {
 		token := tnz.text()
@@ -110,9 +298,46 @@ func (fld *Field) setSliceField(value reflect.Value, str string) error {
 	return tnz.Err()
 }
 
+func (fld *Field) setMapField(value reflect.Value, str string) error {
+	mapType := value.Type()
+	tnz := newSliceTokenizer(str)
+
+	m := reflect.MakeMap(mapType)
+
+	for tnz.scan() {
+		token := tnz.text()
+
+		i := strings.Index(token, ":")
+		if i < 0 {
+			return fmt.Errorf("envconfig: map token %q is not a key:value pair", token)
+		}
+
+		key := reflect.New(mapType.Key()).Elem()
+		if err := fld.parseValue(key, token[:i]); err != nil {
+			return err
+		}
+
+		el := reflect.New(mapType.Elem()).Elem()
+		if err := fld.parseValue(el, token[i+1:]); err != nil {
+			return err
+		}
+
+		m.SetMapIndex(key, el)
+	}
+
+	value.Set(m)
+
+	return tnz.Err()
+}
+
 func (fld *Field) parseValue(v reflect.Value, str string) (err error) {
 	vtype := v.Type()
 
+	// Special case for types with a registered parser
+	if parser, ok := fld.parsers[vtype]; ok {
+		return parseWithParser(v, str, parser)
+	}
+
 	// Special case when the type is a map: we need to make the map
 	switch vtype.Kind() {
 	case reflect.Map:
@@ -121,7 +346,7 @@ func (fld *Field) parseValue(v reflect.Value, str string) (err error) {
 
 	// Special case for Unmarshaler
 	if isUnmarshaler(vtype) {
-		return parseWithUnmarshaler(v, str)
+		return parseWithUnmarshaler(fld, v, str)
 	}
 
 	// Special case for time.Duration
@@ -129,14 +354,26 @@ func (fld *Field) parseValue(v reflect.Value, str string) (err error) {
 		return parseDuration(v, str)
 	}
 
+	// Special case for time.Time
+	if isTimeField(vtype) {
+		return parseTime(v, str, fld.layout, fld.tz)
+	}
+
 	kind := vtype.Kind()
+	switch {
+	case fld.unit == "bytes" && isIntegerKind(kind):
+		return parseByteSizeValue(v, str)
+	case fld.unit != "" && isIntegerKind(kind):
+		return fmt.Errorf("envconfig: unknown unit %q", fld.unit)
+	}
+
 	switch kind {
 	case reflect.Bool:
 		err = parseBoolValue(v, str)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		err = parseIntValue(v, str)
+		err = parseIntValue(v, str, fld.base)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		err = parseUintValue(v, str)
+		err = parseUintValue(v, str, fld.base)
 	case reflect.Float32, reflect.Float64:
 		err = parseFloatValue(v, str)
 	case reflect.Ptr:
@@ -173,30 +410,22 @@ func (fld *Field) parseStruct(value reflect.Value, token string) error {
 }
 
 func (fld *Field) readValue() (string, error) {
-	keys := fld.Keys()
-
-	var str string
-
-	for _, key := range keys {
-		str = os.Getenv(key)
-		if str != "" {
-			break
-		}
-	}
-
-	if str != "" {
-		return str, nil
+	str, key, err := fld.resolve(make(map[*Field]bool))
+	if err != nil {
+		return "", err
 	}
 
-	if fld.defaultVal != "" {
-		return fld.defaultVal, nil
+	fld.key = key
+	if key != "" {
+		fld.warnDeprecated()
 	}
 
-	if fld.optional {
-		return "", nil
+	if str != "" || fld.optional || fld.initial.IsValid() {
+		return str, nil
 	}
 
-	return "", fmt.Errorf("envconfig: keys %s not found", strings.Join(keys, ", "))
+	keys := fld.Keys()
+	return "", fmt.Errorf("envconfig: keys %s not found", strings.Join(append(keys[:len(keys):len(keys)], fld.aliases...), ", "))
 }
 
 type fieldName []string
@@ -222,16 +451,8 @@ func (name fieldName) Keys() (res []string) {
 			buf2.WriteRune('_')
 		}
 
-		n := []rune(part)
-		for i, r := range part {
-			prevOrNextLower := i+1 < len(n) && i-1 > 0 && (unicode.IsLower(n[i+1]) || unicode.IsLower(n[i-1]))
-			if i > 0 && unicode.IsUpper(r) && prevOrNextLower {
-				buf.WriteRune('_')
-			}
-
-			buf.WriteRune(r)
-			buf2.WriteRune(r)
-		}
+		buf.WriteString(strings.Join(splitWords(part), "_"))
+		buf2.WriteString(part)
 	}
 
 	tmp := make(map[string]struct{})
@@ -0,0 +1,52 @@
+package envconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// fileKeySuffix is appended to a key to name the file its value is read from with Options.FileKeys.
+const fileKeySuffix = "_FILE"
+
+// lookupKey returns the value of key or, with Options.FileKeys, the contents of the file named by key_FILE when key is not set.
+// Trailing line breaks are removed from the contents of the file.
+func (fld *Field) lookupKey(key string) (string, error) {
+	if str := os.Getenv(key); str != "" || !fld.state.opts.FileKeys {
+		return str, nil
+	}
+
+	path := os.Getenv(key + fileKeySuffix)
+	if path == "" {
+		return "", nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("envconfig: %s%s: %w", key, fileKeySuffix, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// Files returns the files named by the _FILE keys of the fields with Options.FileKeys, sorted and without duplicates.
+// Reloader.WatchFiles watches them when it is given no paths.
+func (cinfo *ConfInfo) Files() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, fld := range *cinfo {
+		if !fld.state.opts.FileKeys {
+			continue
+		}
+
+		keys := fld.Keys()
+		for _, key := range append(keys[:len(keys):len(keys)], fld.aliases...) {
+			if path := os.Getenv(key + fileKeySuffix); path != "" && !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
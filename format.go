@@ -0,0 +1,136 @@
+package envconfig
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatValue returns v as it would be written in the value of fld, for showing values which were not read from a string.
+func (fld *Field) formatValue(v reflect.Value) string {
+	t := v.Type()
+
+	switch {
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		return ""
+	case isUnmarshaler(t) || hasParser(fld.parsers, t):
+		return formatText(v)
+	case v.Kind() == reflect.Ptr:
+		return fld.formatValue(v.Elem())
+	case isDurationField(t):
+		return v.Convert(durationType).Interface().(time.Duration).String()
+	case isTimeField(t):
+		return formatTime(v.Convert(timeType).Interface().(time.Time), fld.layout)
+	case t == byteSliceType:
+		return encodeBytes(v.Bytes(), fld.encoding)
+	case isByteArray(t):
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return encodeBytes(b, fld.encoding)
+	case fld.unit == "bytes" && isIntegerKind(v.Kind()):
+		if v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64 {
+			return formatByteSize(uint64(v.Int()))
+		}
+		return formatByteSize(v.Uint())
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), formatBase(fld.base))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), formatBase(fld.base))
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, t.Bits())
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fld.formatValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	case reflect.Map:
+		var parts []string
+		for _, key := range v.MapKeys() {
+			parts = append(parts, fld.formatValue(key)+":"+fld.formatValue(v.MapIndex(key)))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	case reflect.Struct:
+		parts := make([]string, v.NumField())
+		for i := range parts {
+			parts[i] = fld.formatValue(v.Field(i))
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// formatBase returns the base to format integers in for the base= tag option, where 0 means the base is given by a prefix.
+func formatBase(base int) int {
+	if base == 0 {
+		return 10
+	}
+	return base
+}
+
+// formatText formats values of types with their own parsing, which can only be formatted if they say how.
+func formatText(v reflect.Value) string {
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		v = v.Addr()
+	}
+
+	switch x := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		if b, err := x.MarshalText(); err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func formatTime(t time.Time, layout string) string {
+	switch name := strings.ToLower(layout); name {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixNano()/1e6, 10)
+	case "unixmicro":
+		return strconv.FormatInt(t.UnixNano()/1e3, 10)
+	case "unixnano":
+		return strconv.FormatInt(t.UnixNano(), 10)
+	default:
+		if l, ok := timeLayouts[name]; ok {
+			layout = l
+		} else if layout == "" {
+			layout = time.RFC3339
+		}
+		return t.Format(layout)
+	}
+}
+
+func encodeBytes(b []byte, encoding string) string {
+	switch encoding {
+	case "base64url":
+		return base64.URLEncoding.EncodeToString(b)
+	case "rawbase64":
+		return base64.RawStdEncoding.EncodeToString(b)
+	case "hex":
+		return hex.EncodeToString(b)
+	case "raw":
+		return string(b)
+	default:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+}
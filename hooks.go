@@ -0,0 +1,71 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AfterReader is the interface implemented by configuration structs which adjust their own values once they are read,
+// for example to normalise them.
+type AfterReader interface {
+	AfterRead() error
+}
+
+// Validator is the interface implemented by configuration structs which check their own values once they are read,
+// for example to enforce rules involving several fields.
+type Validator interface {
+	Validate() error
+}
+
+// node is a struct read by readStruct: the configuration struct itself, a nested struct or an implementation of an interface field.
+type node struct {
+	name  fieldName
+	value reflect.Value
+	gate  *gate
+	depth int
+}
+
+// runHooks calls AfterRead on every struct which implements AfterReader, then Validate on every struct which implements Validator,
+// innermost structs first. Structs behind a closed gate are skipped.
+// Interface fields are set again between the two, so that they hold the changes AfterRead made to implementations which are not pointers.
+func (st *state) runHooks() error {
+	nodes := make([]*node, len(st.nodes))
+	copy(nodes, st.nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].depth > nodes[j].depth
+	})
+
+	for _, n := range nodes {
+		if u, ok := n.hook().(AfterReader); ok && n.gate.isOpen() {
+			if err := u.AfterRead(); err != nil {
+				return n.wrap(err)
+			}
+		}
+	}
+	if err := st.config.setVariants(); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if v, ok := n.hook().(Validator); ok && n.gate.isOpen() {
+			if err := v.Validate(); err != nil {
+				return n.wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+func (n *node) hook() interface{} {
+	if n.value.CanAddr() {
+		return n.value.Addr().Interface()
+	}
+	return n.value.Interface()
+}
+
+func (n *node) wrap(err error) error {
+	if len(n.name) == 0 {
+		return fmt.Errorf("envconfig: %w", err)
+	}
+	return fmt.Errorf("envconfig: %s: %w", n.name, err)
+}
@@ -0,0 +1,135 @@
+package envconfig_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+var errMissingKey = errors.New("TLS key required when TLS cert set")
+
+type hookTLS struct {
+	Cert string `envconfig:"optional"`
+	Key  string `envconfig:"optional"`
+
+	calls *[]string
+}
+
+func (c *hookTLS) AfterRead() error {
+	*c.calls = append(*c.calls, "TLS.AfterRead")
+	c.Cert = strings.TrimSpace(c.Cert)
+	return nil
+}
+
+func (c *hookTLS) Validate() error {
+	*c.calls = append(*c.calls, "TLS.Validate")
+	if c.Cert != "" && c.Key == "" {
+		return errMissingKey
+	}
+	return nil
+}
+
+type hookServer struct {
+	Addr string
+	TLS  hookTLS
+
+	calls []string
+}
+
+func (c *hookServer) AfterRead() error {
+	c.calls = append(c.calls, "Server.AfterRead")
+	c.Addr = strings.ToLower(c.Addr)
+	return nil
+}
+
+func (c *hookServer) Validate() error {
+	c.calls = append(c.calls, "Server.Validate")
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	var conf struct {
+		Server hookServer
+	}
+	conf.Server.TLS.calls = &conf.Server.calls
+
+	os.Setenv("SERVER_ADDR", "LOCALHOST:443")
+	os.Setenv("SERVER_TLS_CERT", " /etc/tls.crt ")
+	os.Setenv("SERVER_TLS_KEY", "/etc/tls.key")
+
+	err := envconfig.InitWithOptions(&conf, envconfig.Options{AllowUnexported: true})
+	require.Nil(t, err)
+	require.Equal(t, "localhost:443", conf.Server.Addr)
+	require.Equal(t, "/etc/tls.crt", conf.Server.TLS.Cert)
+	require.Equal(t, []string{"TLS.AfterRead", "Server.AfterRead", "TLS.Validate", "Server.Validate"}, conf.Server.calls)
+
+	os.Setenv("SERVER_TLS_KEY", "")
+	conf.Server.calls = nil
+	conf.Server.TLS.Key = ""
+
+	err = envconfig.InitWithOptions(&conf, envconfig.Options{AllowUnexported: true})
+	require.Equal(t, "envconfig: Server.TLS: TLS key required when TLS cert set", err.Error())
+	require.True(t, errors.Is(err, errMissingKey))
+	require.Equal(t, []string{"TLS.AfterRead", "Server.AfterRead", "TLS.Validate"}, conf.Server.calls)
+}
+
+type hookRoot struct {
+	Name string
+}
+
+func (c hookRoot) Validate() error {
+	if c.Name == "forbidden" {
+		return errors.New("name is forbidden")
+	}
+	return nil
+}
+
+func TestRootValidator(t *testing.T) {
+	var conf hookRoot
+
+	os.Setenv("NAME", "forbidden")
+
+	err := envconfig.Init(&conf)
+	require.Equal(t, "envconfig: name is forbidden", err.Error())
+
+	os.Setenv("NAME", "")
+}
+
+type probeStore interface {
+	Location() string
+}
+
+type probeS3 struct {
+	Bucket string
+}
+
+func (s probeS3) Location() string { return "s3://" + s.Bucket }
+
+func (s *probeS3) AfterRead() error {
+	s.Bucket = strings.ToUpper(s.Bucket)
+	return nil
+}
+
+func init() {
+	envconfig.Register((*probeStore)(nil), "s3", probeS3{})
+}
+
+func TestHooksOnValueVariant(t *testing.T) {
+	var conf struct {
+		Probe probeStore
+	}
+
+	os.Setenv("PROBE_DRIVER", "s3")
+	os.Setenv("PROBE_S3_BUCKET", "abc")
+
+	err := envconfig.Init(&conf)
+	require.Nil(t, err)
+	require.Equal(t, probeS3{Bucket: "ABC"}, conf.Probe)
+
+	os.Setenv("PROBE_DRIVER", "")
+	os.Setenv("PROBE_S3_BUCKET", "")
+}
@@ -0,0 +1,142 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// interpolate expands the ${NAME} and ${NAME:-fallback} references in s using lookup.
+// The fallback is used when NAME is empty, and may contain references itself. $$ stands for a literal $.
+func interpolate(s string, lookup func(name string) (string, error)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			buf.WriteByte('$')
+			i++
+		case '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("envconfig: unterminated reference in %q", s)
+			}
+
+			name, fallback := s[i+2:end], ""
+			hasFallback := false
+			if j := strings.Index(name, ":-"); j >= 0 {
+				name, fallback, hasFallback = name[:j], name[j+2:], true
+			}
+			if name == "" {
+				return "", fmt.Errorf("envconfig: empty reference in %q", s)
+			}
+
+			val, err := lookup(name)
+			if err == nil && val == "" && hasFallback {
+				val, err = interpolate(fallback, lookup)
+			}
+			if err != nil {
+				return "", err
+			}
+
+			buf.WriteString(val)
+			i = end
+		default:
+			buf.WriteByte('$')
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// closingBrace returns the index of the brace closing the reference starting at i in s, or -1.
+func closingBrace(s string, i int) int {
+	depth := 1
+	for ; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "$$"):
+			i++
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// resolve returns the value of fld before parsing, along with the key it was read from:
+// the value of the first key set, or read from a file with Options.FileKeys, or the default value with an empty key.
+// Default value providers are only called when no key is set.
+// References to the keys of other fields are resolved through those fields, and visiting holds the fields being resolved.
+func (fld *Field) resolve(visiting map[*Field]bool) (string, string, error) {
+	if visiting[fld] {
+		return "", "", fmt.Errorf("envconfig: %s: reference cycle", fld.Name())
+	}
+	visiting[fld] = true
+	defer delete(visiting, fld)
+
+	keys := fld.Keys()
+	for _, key := range append(keys[:len(keys):len(keys)], fld.aliases...) {
+		str, err := fld.lookupKey(key)
+		if err != nil {
+			return "", key, err
+		}
+		if str == "" {
+			continue
+		}
+
+		if fld.state.interpolate {
+			str, err = fld.interpolate(str, visiting)
+		}
+		return str, key, err
+	}
+
+	if fld.initial.IsValid() {
+		return fld.Default(), "", nil
+	}
+
+	defaultVal := fld.ProfileDefault(fld.state.profile())
+	str, err := fld.evalDefault(defaultVal)
+	if _, ok := defaultProvider(defaultVal); err == nil && !ok {
+		str, err = fld.interpolate(str, visiting)
+	}
+	return str, "", err
+}
+
+func (fld *Field) interpolate(s string, visiting map[*Field]bool) (string, error) {
+	return interpolate(s, func(name string) (string, error) {
+		if ref := fld.state.config.fieldWithKey(name); ref != nil {
+			str, _, err := ref.resolve(visiting)
+			return str, err
+		}
+		return os.Getenv(name), nil
+	})
+}
+
+// fieldWithKey returns the field read from key, or nil.
+func (cinfo *ConfInfo) fieldWithKey(key string) *Field {
+	if cinfo == nil {
+		return nil
+	}
+	for _, fld := range *cinfo {
+		for _, k := range fld.Keys() {
+			if k == key {
+				return fld
+			}
+		}
+	}
+	return nil
+}
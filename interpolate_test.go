@@ -0,0 +1,45 @@
+package envconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{"HOST": "localhost", "PORT": "8080", "EMPTY": ""}
+	lookup := func(name string) (string, error) {
+		return vars[name], nil
+	}
+
+	cases := map[string]string{
+		"plain":                       "plain",
+		"${HOST}:${PORT}":             "localhost:8080",
+		"${EMPTY:-fallback}":          "fallback",
+		"${HOST:-fallback}":           "localhost",
+		"${EMPTY:-${HOST}:${PORT}}":   "localhost:8080",
+		"${MISSING}":                  "",
+		"$$HOME and $PATH":            "$HOME and $PATH",
+		"$${HOST}":                    "${HOST}",
+		"${EMPTY:-a $$ sign}":         "a $ sign",
+		"cost: 5$":                    "cost: 5$",
+		"${EMPTY:-}":                  "",
+		"http://${HOST}/${EMPTY:-x}/": "http://localhost/x/",
+	}
+
+	for s, expected := range cases {
+		res, err := interpolate(s, lookup)
+		require.Nil(t, err, s)
+		require.Equal(t, expected, res, s)
+	}
+
+	_, err := interpolate("${HOST", lookup)
+	require.Equal(t, `envconfig: unterminated reference in "${HOST"`, err.Error())
+
+	_, err = interpolate("${:-x}", lookup)
+	require.Equal(t, `envconfig: empty reference in "${:-x}"`, err.Error())
+
+	_, err = interpolate("${HOST}", func(string) (string, error) { return "", errors.New("boom") })
+	require.Equal(t, "boom", err.Error())
+}
@@ -0,0 +1,143 @@
+//go:build go1.18
+// +build go1.18
+
+package envconfig
+
+import "reflect"
+
+// Option sets a field of Options, for use with Load and MustLoad.
+type Option func(opts *Options)
+
+// Load reads a configuration struct of type T from environment variables and returns it.
+// T must be a struct type.
+func Load[T any](opts ...Option) (T, error) {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var conf T
+	if err := InitWithOptions(&conf, o); err != nil {
+		var zero T
+		return zero, err
+	}
+	return conf, nil
+}
+
+// MustLoad is like Load but panics if the configuration cannot be read.
+func MustLoad[T any](opts ...Option) T {
+	conf, err := Load[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return conf
+}
+
+// WithOptions sets all of the options at once. Options given after it override its fields.
+func WithOptions(o Options) Option {
+	return func(opts *Options) {
+		*opts = o
+	}
+}
+
+// WithPrefix sets Options.Prefix.
+func WithPrefix(prefix string) Option {
+	return func(opts *Options) {
+		opts.Prefix = prefix
+	}
+}
+
+// WithPrefixes sets Options.Prefixes.
+func WithPrefixes(prefixes ...string) Option {
+	return func(opts *Options) {
+		opts.Prefixes = prefixes
+	}
+}
+
+// WithAllOptional sets Options.AllOptional.
+func WithAllOptional() Option {
+	return func(opts *Options) {
+		opts.AllOptional = true
+	}
+}
+
+// WithAllowUnexported sets Options.AllowUnexported.
+func WithAllowUnexported() Option {
+	return func(opts *Options) {
+		opts.AllowUnexported = true
+	}
+}
+
+// WithNameMapper sets Options.NameMapper.
+func WithNameMapper(m NameMapper) Option {
+	return func(opts *Options) {
+		opts.NameMapper = m
+	}
+}
+
+// WithCustomNames sets Options.CustomNames.
+func WithCustomNames(mode CustomNameMode) Option {
+	return func(opts *Options) {
+		opts.CustomNames = mode
+	}
+}
+
+// WithInterpolate sets Options.Interpolate.
+func WithInterpolate() Option {
+	return func(opts *Options) {
+		opts.Interpolate = true
+	}
+}
+
+// WithFileKeys sets Options.FileKeys.
+func WithFileKeys() Option {
+	return func(opts *Options) {
+		opts.FileKeys = true
+	}
+}
+
+// WithLeaveNil sets Options.LeaveNil.
+func WithLeaveNil() Option {
+	return func(opts *Options) {
+		opts.LeaveNil = true
+	}
+}
+
+// WithProfile sets Options.Profile.
+func WithProfile(profile string) Option {
+	return func(opts *Options) {
+		opts.Profile = profile
+	}
+}
+
+// WithProfileKey sets Options.ProfileKey.
+func WithProfileKey(key string) Option {
+	return func(opts *Options) {
+		opts.ProfileKey = key
+	}
+}
+
+// WithWarn sets Options.Warn.
+func WithWarn(fn func(fld *Field, msg string)) Option {
+	return func(opts *Options) {
+		opts.Warn = fn
+	}
+}
+
+// WithParser adds fn to Options.Parsers as the parser of the type P.
+func WithParser[P any](fn func(s string) (P, error)) Option {
+	return func(opts *Options) {
+		parsers := make(map[reflect.Type]ParseFunc, len(opts.Parsers)+1)
+		for t, p := range opts.Parsers {
+			parsers[t] = p
+		}
+		parsers[reflect.TypeOf((*P)(nil)).Elem()] = func(s string) (interface{}, error) {
+			v, err := fn(s)
+			if err != nil {
+				return nil, err
+			}
+			return v, nil
+		}
+		opts.Parsers = parsers
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go1.18
+// +build go1.18
+
+package envconfig_test
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type loadConfig struct {
+	Name     string
+	Endpoint url.URL
+	Level    string `envconfig:"default=info,default.prod=warn"`
+}
+
+func TestLoad(t *testing.T) {
+	os.Setenv("LOAD_NAME", "api")
+	os.Setenv("LOAD_ENDPOINT", "https://example.com/v1")
+
+	parseURL := envconfig.WithParser(func(s string) (url.URL, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return *u, nil
+	})
+
+	conf, err := envconfig.Load[loadConfig](envconfig.WithPrefix("LOAD"), envconfig.WithProfile("prod"), parseURL)
+	require.Nil(t, err)
+	require.Equal(t, "api", conf.Name)
+	require.Equal(t, "example.com", conf.Endpoint.Host)
+	require.Equal(t, "warn", conf.Level)
+
+	os.Setenv("LOAD_NAME", "")
+	os.Setenv("LOAD_ENDPOINT", "")
+
+	_, err = envconfig.Load[loadConfig](envconfig.WithOptions(envconfig.Options{Prefix: "LOAD"}), parseURL)
+	require.Equal(t, "envconfig: keys LOAD_NAME, load_name not found", err.Error())
+
+	conf = envconfig.MustLoad[loadConfig](envconfig.WithPrefix("LOAD"), envconfig.WithAllOptional(), parseURL)
+	require.Equal(t, "", conf.Name)
+	require.Equal(t, "info", conf.Level)
+
+	require.Panics(t, func() {
+		envconfig.MustLoad[loadConfig](envconfig.WithPrefix("LOAD"), parseURL)
+	})
+
+	_, err = envconfig.Load[string]()
+	require.Equal(t, envconfig.ErrInvalidValueKind, err)
+}
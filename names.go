@@ -0,0 +1,82 @@
+package envconfig
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NameMapper chooses the keys tried when reading a field.
+// Keys is called with the path of the field: the prefix, if any, followed by the names of the struct fields leading to it,
+// for example ["Server", "RemoteHost"]. Keys are tried in the order they are returned.
+// Fields with a custom name in their tag do not use the NameMapper.
+type NameMapper interface {
+	Keys(path []string) []string
+}
+
+// NameMapperFunc is an adapter to allow the use of an ordinary function as a NameMapper.
+type NameMapperFunc func(path []string) []string
+
+// Keys returns f(path).
+func (f NameMapperFunc) Keys(path []string) []string {
+	return f(path)
+}
+
+var (
+	// DefaultNames is the NameMapper used when Options.NameMapper is nil.
+	// It tries the upper and lower case variants of the path joined by underscores,
+	// with and without underscores between words: REMOTEHOST, REMOTE_HOST, remotehost and remote_host for RemoteHost.
+	DefaultNames NameMapper = NameMapperFunc(func(path []string) []string {
+		return fieldName(path).Keys()
+	})
+
+	// UpperSnake maps a path to the single key made of its upper case words joined by underscores,
+	// SERVER_REMOTE_HOST for Server.RemoteHost.
+	UpperSnake NameMapper = NameMapperFunc(func(path []string) []string {
+		return []string{joinPath(path, "_", "_", strings.ToUpper)}
+	})
+
+	// NestedUpperSnake is like UpperSnake but separates nested structs with a double underscore,
+	// SERVER__REMOTE_HOST for Server.RemoteHost.
+	NestedUpperSnake NameMapper = NameMapperFunc(func(path []string) []string {
+		return []string{joinPath(path, "__", "_", strings.ToUpper)}
+	})
+
+	// ExactNames maps a path to the field names as written joined by underscores, Server_RemoteHost for Server.RemoteHost.
+	ExactNames NameMapper = NameMapperFunc(func(path []string) []string {
+		return []string{strings.Join(path, "_")}
+	})
+
+	// KebabCase maps a path to its lower case words joined by dashes, with nested structs separated by dots,
+	// server.remote-host for Server.RemoteHost. It suits sources other than the environment, such as files.
+	KebabCase NameMapper = NameMapperFunc(func(path []string) []string {
+		return []string{joinPath(path, ".", "-", strings.ToLower)}
+	})
+)
+
+// joinPath joins the parts of path with sep, after separating their words with wordSep and applying fn.
+func joinPath(path []string, sep, wordSep string, fn func(string) string) string {
+	parts := make([]string, len(path))
+	for i, part := range path {
+		parts[i] = fn(strings.Join(splitWords(part), wordSep))
+	}
+	return strings.Join(parts, sep)
+}
+
+// splitWords splits a field name on the boundaries between words, as in Remote and Host for RemoteHost.
+// An upper case letter starts a new word when it follows or precedes a lower case letter,
+// so that acronyms are kept whole: URLPath is split into URL and Path.
+func splitWords(part string) []string {
+	var words []string
+
+	n := []rune(part)
+	start := 0
+	for i, r := range n {
+		prevOrNextLower := i+1 < len(n) && i-1 > 0 && (unicode.IsLower(n[i+1]) || unicode.IsLower(n[i-1]))
+		if i > 0 && unicode.IsUpper(r) && prevOrNextLower {
+			words = append(words, string(n[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(n[start:]))
+}
@@ -0,0 +1,166 @@
+package envconfig_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameMappers(t *testing.T) {
+	var conf struct {
+		Cassandra struct {
+			SSLCert string
+			Name    string `envconfig:"cassandraMyName"`
+		}
+	}
+
+	cases := []struct {
+		mapper envconfig.NameMapper
+		keys   []string
+	}{
+		{nil, []string{"APP_CASSANDRA_SSLCERT", "APP_CASSANDRA_SSL_CERT", "app_cassandra_ssl_cert", "app_cassandra_sslcert"}},
+		{envconfig.DefaultNames, []string{"APP_CASSANDRA_SSLCERT", "APP_CASSANDRA_SSL_CERT", "app_cassandra_ssl_cert", "app_cassandra_sslcert"}},
+		{envconfig.UpperSnake, []string{"APP_CASSANDRA_SSL_CERT"}},
+		{envconfig.NestedUpperSnake, []string{"APP__CASSANDRA__SSL_CERT"}},
+		{envconfig.ExactNames, []string{"App_Cassandra_SSLCert"}},
+		{envconfig.KebabCase, []string{"app.cassandra.ssl-cert"}},
+	}
+
+	for _, c := range cases {
+		cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "App", NameMapper: c.mapper})
+		require.Nil(t, err)
+		require.Equal(t, c.keys, (*cinfo)[0].Keys())
+		require.Equal(t, []string{"cassandraMyName"}, (*cinfo)[1].Keys())
+	}
+}
+
+func TestNameMapperFunc(t *testing.T) {
+	var conf struct {
+		Server struct {
+			RemoteHost string
+		}
+	}
+
+	os.Setenv("SERVER/REMOTEHOST", "localhost")
+
+	err := envconfig.InitWithOptions(&conf, envconfig.Options{
+		NameMapper: envconfig.NameMapperFunc(func(path []string) []string {
+			return []string{strings.ToUpper(strings.Join(path, "/"))}
+		}),
+	})
+	require.Nil(t, err)
+	require.Equal(t, "localhost", conf.Server.RemoteHost)
+
+	conf.Server.RemoteHost = ""
+	err = envconfig.InitWithOptions(&conf, envconfig.Options{NameMapper: envconfig.UpperSnake})
+	require.Equal(t, "envconfig: keys SERVER_REMOTE_HOST not found", err.Error())
+
+	os.Setenv("SERVER/REMOTEHOST", "")
+}
+
+type tlsConfig struct {
+	Cert string
+	Key  string `envconfig:"KEY_FILE"`
+}
+
+type CommonConfig struct {
+	LogLevel string
+}
+
+func TestPrefixAndInline(t *testing.T) {
+	var conf struct {
+		CommonConfig `envconfig:"inline"`
+		Server       struct {
+			TLS tlsConfig `envconfig:"prefix=Secure"`
+		}
+	}
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "App", NameMapper: envconfig.UpperSnake})
+	require.Nil(t, err)
+
+	require.Equal(t, "App.CommonConfig.LogLevel", (*cinfo)[0].Name())
+	require.Equal(t, []string{"APP_LOG_LEVEL"}, (*cinfo)[0].Keys())
+	require.Equal(t, "App.Server.TLS.Cert", (*cinfo)[1].Name())
+	require.Equal(t, []string{"APP_SERVER_SECURE_CERT"}, (*cinfo)[1].Keys())
+	require.Equal(t, []string{"KEY_FILE"}, (*cinfo)[2].Keys())
+
+	_, err = envconfig.Parse(&struct {
+		TLS tlsConfig `envconfig:"prefix="`
+	}{})
+	require.Equal(t, `envconfig: empty prefix in tag option "prefix=", use inline instead`, err.Error())
+}
+
+func TestCustomNames(t *testing.T) {
+	var conf struct {
+		Server struct {
+			TLS tlsConfig
+		}
+	}
+
+	cases := map[envconfig.CustomNameMode]string{
+		envconfig.ExactCustomNames:    "KEY_FILE",
+		envconfig.PrefixedCustomNames: "APP_KEY_FILE",
+		envconfig.RelativeCustomNames: "APP_SERVER_TLS_KEY_FILE",
+	}
+
+	for mode, key := range cases {
+		cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "App", NameMapper: envconfig.UpperSnake, CustomNames: mode})
+		require.Nil(t, err)
+		require.Equal(t, []string{key}, (*cinfo)[1].Keys())
+	}
+
+	os.Setenv("SERVER_TLS_CERT", "cert.pem")
+	os.Setenv("SERVER_TLS_KEY_FILE", "key.pem")
+
+	err := envconfig.InitWithOptions(&conf, envconfig.Options{CustomNames: envconfig.RelativeCustomNames})
+	require.Nil(t, err)
+	require.Equal(t, "key.pem", conf.Server.TLS.Key)
+
+	os.Setenv("SERVER_TLS_CERT", "")
+	os.Setenv("SERVER_TLS_KEY_FILE", "")
+}
+
+func TestPrefixes(t *testing.T) {
+	var conf struct {
+		Backlog int
+	}
+
+	var warnings []string
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{
+		Prefixes:   []string{"NewApp", "Legacy", ""},
+		NameMapper: envconfig.UpperSnake,
+		Warn: func(fld *envconfig.Field, msg string) {
+			warnings = append(warnings, msg)
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "NewApp.Backlog", (*cinfo)[0].Name())
+	require.Equal(t, []string{"NEW_APP_BACKLOG", "LEGACY_BACKLOG", "BACKLOG"}, (*cinfo)[0].Keys())
+
+	os.Setenv("LEGACY_BACKLOG", "8080")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, 8080, conf.Backlog)
+	require.Equal(t, "LEGACY_BACKLOG", (*cinfo)[0].Key())
+	require.Equal(t, []string{"envconfig: LEGACY_BACKLOG uses a fallback prefix: use NEW_APP_BACKLOG"}, warnings)
+
+	warnings = nil
+	os.Setenv("NEW_APP_BACKLOG", "9090")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, 9090, conf.Backlog)
+	require.Equal(t, "NEW_APP_BACKLOG", (*cinfo)[0].Key())
+	require.Nil(t, warnings)
+
+	os.Setenv("LEGACY_BACKLOG", "")
+	os.Setenv("NEW_APP_BACKLOG", "")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: keys NEW_APP_BACKLOG, LEGACY_BACKLOG, BACKLOG not found", err.Error())
+
+	cinfo, err = envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "App", Prefixes: []string{"Old"}, NameMapper: envconfig.UpperSnake})
+	require.Nil(t, err)
+	require.Equal(t, []string{"APP_BACKLOG", "OLD_BACKLOG"}, (*cinfo)[0].Keys())
+}
@@ -0,0 +1,122 @@
+package envconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Relation is a requirement between two fields, set with the required_if=, requires= or conflicts= tag options.
+type Relation struct {
+	// Kind is one of required_if, requires or conflicts.
+	Kind string
+	// Field is the field the relation refers to.
+	Field *Field
+	// Values are the values of Field which make a required_if field mandatory.
+	Values []string
+
+	path string
+}
+
+func isRelation(name string) bool {
+	switch name {
+	case "required_if", "requires", "conflicts":
+		return true
+	}
+	return false
+}
+
+// parseRelations parses the argument of a relation tag option: a list of field paths separated by |,
+// or for required_if, a field path and a list of values separated by |, such as Mode:tls|mtls.
+func parseRelations(kind, arg string) ([]*Relation, error) {
+	if kind == "required_if" {
+		i := strings.Index(arg, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("envconfig: invalid tag option %s=%s, expected a field and values such as Mode:tls", kind, arg)
+		}
+		return []*Relation{{Kind: kind, path: arg[:i], Values: strings.Split(arg[i+1:], "|")}}, nil
+	}
+
+	var res []*Relation
+	for _, path := range strings.Split(arg, "|") {
+		if path == "" {
+			return nil, fmt.Errorf("envconfig: invalid tag option %s=%s, expected a field", kind, arg)
+		}
+		res = append(res, &Relation{Kind: kind, path: path})
+	}
+	return res, nil
+}
+
+// Relations returns the requirements between this field and others.
+func (fld *Field) Relations() []Relation {
+	var res []Relation
+	for _, r := range fld.relations {
+		res = append(res, *r)
+	}
+	return res
+}
+
+// resolveRelations finds the fields referred to by relations.
+// A path is looked up relative to the struct containing the field first, then relative to the configuration struct.
+func (cinfo *ConfInfo) resolveRelations(root fieldName) error {
+	for _, fld := range *cinfo {
+		for _, r := range fld.relations {
+			parts := strings.Split(r.path, ".")
+			r.Field = cinfo.lookup(append(fld.name[:len(fld.name)-1:len(fld.name)-1], parts...))
+			if r.Field == nil {
+				r.Field = cinfo.lookup(append(root[:len(root):len(root)], parts...))
+			}
+			if r.Field == nil {
+				return fmt.Errorf("envconfig: %s: %s refers to unknown field %s", fld.Name(), r.Kind, r.path)
+			}
+		}
+	}
+	return nil
+}
+
+func (cinfo *ConfInfo) lookup(name fieldName) *Field {
+	for _, fld := range *cinfo {
+		if fld.name.String() == name.String() {
+			return fld
+		}
+	}
+	return nil
+}
+
+func (fld *Field) isSet() bool {
+	return fld.gate.isOpen() && fld.strValue != ""
+}
+
+// isSetByKey returns whether the value of fld was read from one of its keys, rather than from a default value.
+func (fld *Field) isSetByKey() bool {
+	return fld.gate.isOpen() && fld.key != ""
+}
+
+// is reports whether the field is set to one of values.
+func (fld *Field) is(values []string) bool {
+	if !fld.isSet() {
+		return false
+	}
+	for _, v := range values {
+		if fld.strValue == v || fmt.Sprint(fld.value.Interface()) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (fld *Field) checkRelations() error {
+	if !fld.gate.isOpen() {
+		return nil
+	}
+	for _, r := range fld.relations {
+		switch {
+		case r.Kind == "required_if" && !fld.isSet() && r.Field.is(r.Values):
+			return fmt.Errorf("envconfig: keys %s not found, required when %s is %s", strings.Join(fld.Keys(), ", "), r.Field.Name(), r.Field.strValue)
+		case r.Kind == "requires" && fld.isSetByKey() && !r.Field.isSet():
+			return fmt.Errorf("envconfig: %s requires %s to be set", fld.Name(), r.Field.Name())
+		case r.Kind == "conflicts" && fld.isSetByKey() && r.Field.isSetByKey():
+			return fmt.Errorf("envconfig: %s conflicts with %s", fld.Name(), r.Field.Name())
+		}
+	}
+	return nil
+}
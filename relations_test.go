@@ -0,0 +1,158 @@
+package envconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type authConfig struct {
+	Auth struct {
+		Mode     string `envconfig:"oneof=none|tls|token"`
+		Cert     string `envconfig:"required_if=Mode:tls"`
+		Token    string `envconfig:"required_if=Mode:token,conflicts=Password"`
+		User     string `envconfig:"optional,requires=Password"`
+		Password string `envconfig:"optional"`
+	}
+	Debug bool `envconfig:"optional,conflicts=Auth.Token"`
+}
+
+func TestRelations(t *testing.T) {
+	env := func(mode, cert, token, user, password, debug string) {
+		os.Setenv("AUTH_MODE", mode)
+		os.Setenv("AUTH_CERT", cert)
+		os.Setenv("AUTH_TOKEN", token)
+		os.Setenv("AUTH_USER", user)
+		os.Setenv("AUTH_PASSWORD", password)
+		os.Setenv("DEBUG", debug)
+	}
+	defer env("", "", "", "", "", "")
+
+	env("none", "", "", "", "", "")
+	require.Nil(t, envconfig.Init(&authConfig{}))
+
+	env("tls", "", "", "", "", "")
+	err := envconfig.Init(&authConfig{})
+	require.Equal(t, "envconfig: keys AUTH_CERT, auth_cert not found, required when Auth.Mode is tls", err.Error())
+
+	env("tls", "/etc/tls.crt", "", "", "", "")
+	require.Nil(t, envconfig.Init(&authConfig{}))
+
+	env("token", "", "", "", "", "")
+	err = envconfig.Init(&authConfig{})
+	require.Equal(t, "envconfig: keys AUTH_TOKEN, auth_token not found, required when Auth.Mode is token", err.Error())
+
+	env("token", "", "abc", "", "secret", "")
+	err = envconfig.Init(&authConfig{})
+	require.Equal(t, "envconfig: Auth.Token conflicts with Auth.Password", err.Error())
+
+	env("none", "", "", "admin", "", "")
+	err = envconfig.Init(&authConfig{})
+	require.Equal(t, "envconfig: Auth.User requires Auth.Password to be set", err.Error())
+
+	env("token", "", "abc", "", "", "true")
+	err = envconfig.Init(&authConfig{})
+	require.Equal(t, "envconfig: Debug conflicts with Auth.Token", err.Error())
+
+	cinfo, err := envconfig.Parse(&authConfig{})
+	require.Nil(t, err)
+	require.Equal(t, true, (*cinfo)[1].Optional())
+	relations := (*cinfo)[2].Relations()
+	require.Equal(t, 2, len(relations))
+	require.Equal(t, "required_if", relations[0].Kind)
+	require.Equal(t, "Auth.Mode", relations[0].Field.Name())
+	require.Equal(t, []string{"token"}, relations[0].Values)
+	require.Equal(t, "Auth.Password", relations[1].Field.Name())
+}
+
+func TestUnknownRelation(t *testing.T) {
+	var conf struct {
+		User string `envconfig:"requires=Pasword"`
+	}
+
+	_, err := envconfig.Parse(&conf)
+	require.Equal(t, "envconfig: User: requires refers to unknown field Pasword", err.Error())
+
+	var conf2 struct {
+		Cert string `envconfig:"required_if=Mode"`
+	}
+
+	_, err = envconfig.Parse(&conf2)
+	require.Equal(t, "envconfig: invalid tag option required_if=Mode, expected a field and values such as Mode:tls", err.Error())
+}
+
+type relStore interface{}
+
+type relS3 struct {
+	Bucket string
+	Cert   string `envconfig:"required_if=Mode:tls"`
+}
+
+type relDisk struct {
+	Path string
+}
+
+func init() {
+	envconfig.Register((*relStore)(nil), "s3", relS3{})
+	envconfig.Register((*relStore)(nil), "disk", relDisk{})
+}
+
+type relGateConfig struct {
+	Mode    string
+	Metrics struct {
+		Enabled bool   `envconfig:"optional"`
+		Cert    string `envconfig:"required_if=Mode:tls"`
+	} `envconfig:"gate=Enabled"`
+	Store relStore
+}
+
+func TestRelationsBehindClosedGate(t *testing.T) {
+
+	os.Setenv("RELGATE_MODE", "tls")
+	os.Setenv("RELGATE_STORE_DRIVER", "disk")
+	os.Setenv("RELGATE_STORE_DISK_PATH", "/var/lib/store")
+	defer func() {
+		os.Setenv("RELGATE_MODE", "")
+		os.Setenv("RELGATE_STORE_DRIVER", "")
+		os.Setenv("RELGATE_STORE_DISK_PATH", "")
+		os.Setenv("RELGATE_METRICS_ENABLED", "")
+	}()
+
+	require.Nil(t, envconfig.InitWithPrefix(&relGateConfig{}, "RELGATE"))
+
+	os.Setenv("RELGATE_METRICS_ENABLED", "true")
+	err := envconfig.InitWithPrefix(&relGateConfig{}, "RELGATE")
+	require.Equal(t, "envconfig: keys RELGATE_METRICS_CERT, relgate_metrics_cert not found, required when RELGATE.Mode is tls", err.Error())
+
+	os.Setenv("RELGATE_METRICS_ENABLED", "")
+	os.Setenv("RELGATE_STORE_DRIVER", "s3")
+	os.Setenv("RELGATE_STORE_S3_BUCKET", "assets")
+	defer os.Setenv("RELGATE_STORE_S3_BUCKET", "")
+	err = envconfig.InitWithPrefix(&relGateConfig{}, "RELGATE")
+	require.Equal(t, "envconfig: keys RELGATE_STORE_S3_CERT, relgate_store_s3_cert not found, required when RELGATE.Mode is tls", err.Error())
+}
+
+func TestRelationsWithDefaults(t *testing.T) {
+	type config struct {
+		Mode string `envconfig:"ZZ_MODE,default=plain,conflicts=Cert"`
+		Cert string `envconfig:"ZZ_CERT,optional"`
+		User string `envconfig:"ZZ_USER,optional,requires=Role"`
+		Role string `envconfig:"ZZ_ROLE,default=reader"`
+	}
+
+	os.Setenv("ZZ_CERT", "/etc/tls.crt")
+	os.Setenv("ZZ_USER", "admin")
+	defer func() {
+		os.Setenv("ZZ_MODE", "")
+		os.Setenv("ZZ_CERT", "")
+		os.Setenv("ZZ_USER", "")
+	}()
+
+	require.Nil(t, envconfig.Init(&config{}))
+
+	os.Setenv("ZZ_MODE", "tls")
+	err := envconfig.Init(&config{})
+	require.Equal(t, "envconfig: Mode conflicts with Cert", err.Error())
+}
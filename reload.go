@@ -0,0 +1,93 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the values of secret fields in the changes returned by Reload and in the docs subpackage.
+const Redacted = "[redacted]"
+
+// Change describes a field whose value was changed by Reload.
+// Old and New are formatted as they would be written in a key, and are redacted for secret fields.
+type Change struct {
+	Field *Field
+	Old   string
+	New   string
+}
+
+// Reload reads the configuration again like Read, but into a fresh copy of the conf object.
+// Only once every field of the copy is read and its constraints and hooks succeeded
+// are the values of the fields set in the conf object, so that it is left untouched when Reload returns an error.
+// AfterRead and Validate are called on the copy, and changes they make to values which are not fields are not applied.
+// Fields behind a gate which closed are set to their zero value.
+// Default value providers are not called again for the fields they were already called for,
+// so that values such as @random or @hostname do not change on every Reload.
+//
+// Reload returns the fields whose value changed.
+// With Options.RestrictReload, Reload fails without applying anything if a field without the reloadable tag option changed,
+// and also returns the changes in that case.
+func (cinfo *ConfInfo) Reload() ([]Change, error) {
+	if len(*cinfo) == 0 {
+		return nil, nil
+	}
+	changes, _, err := cinfo.reload(true)
+	return changes, err
+}
+
+// reload implements Reload, returning a pointer to the copy of the conf object which was read.
+// Options.RestrictReload is ignored unless restrict is true.
+func (cinfo *ConfInfo) reload(restrict bool) ([]Change, reflect.Value, error) {
+	st := (*cinfo)[0].state
+
+	ptr := reflect.New(st.root.Type())
+	next, err := ParseWithOptions(ptr.Interface(), st.opts)
+	if err != nil {
+		return nil, ptr, err
+	}
+	for i, fld := range *next {
+		fld.initial = (*cinfo)[i].initial
+		fld.provided = (*cinfo)[i].provided
+	}
+	if err := next.read(); err != nil {
+		return nil, ptr, err
+	}
+
+	var changes []Change
+	var fixed []string
+	for i, fld := range *cinfo {
+		nfld := (*next)[i]
+		old, cur := fld.formatValue(fld.value), nfld.formatValue(nfld.value)
+		if old == cur {
+			continue
+		}
+		if restrict && st.opts.RestrictReload && !fld.reloadable {
+			fixed = append(fixed, fld.Name())
+		}
+		if fld.secret {
+			old, cur = Redacted, Redacted
+		}
+		changes = append(changes, Change{Field: fld, Old: old, New: cur})
+	}
+	if fixed != nil {
+		return changes, ptr, fmt.Errorf("envconfig: %s cannot be reloaded", strings.Join(fixed, ", "))
+	}
+
+	for i, fld := range *cinfo {
+		nfld := (*next)[i]
+		fld.value.Set(nfld.value)
+		fld.strValue, fld.key, fld.provided = nfld.strValue, nfld.key, nfld.provided
+	}
+
+	nst := (*next)[0].state
+	for i, s := range st.slots {
+		s.set = nst.slots[i].set
+		if s.set {
+			s.target.Set(s.alloc)
+		} else {
+			s.target.Set(reflect.Zero(s.target.Type()))
+		}
+	}
+	return changes, ptr, cinfo.setVariants()
+}
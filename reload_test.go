@@ -0,0 +1,150 @@
+package envconfig_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadConfig struct {
+	Host     string
+	Port     int    `envconfig:"min=1"`
+	Password string `envconfig:"optional,secret"`
+	Limits   struct {
+		Rate int `envconfig:"default=10"`
+	}
+	TLS *struct {
+		Cert string
+	}
+
+	version int `envconfig:"-"`
+}
+
+func (c *reloadConfig) Validate() error {
+	if c.Host == "forbidden" {
+		return errors.New("forbidden host")
+	}
+	return nil
+}
+
+func TestReload(t *testing.T) {
+	conf := reloadConfig{version: 1}
+
+	os.Setenv("RL_HOST", "localhost")
+	os.Setenv("RL_PORT", "80")
+	os.Setenv("RL_PASSWORD", "hunter2")
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "RL", LeaveNil: true, AllowUnexported: true})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+
+	changes, err := cinfo.Reload()
+	require.Nil(t, err)
+	require.Nil(t, changes)
+
+	os.Setenv("RL_HOST", "example.com")
+	os.Setenv("RL_PASSWORD", "hunter3")
+	os.Setenv("RL_TLS_CERT", "cert.pem")
+	changes, err = cinfo.Reload()
+	require.Nil(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, "RL.Host", changes[0].Field.Name())
+	require.Equal(t, "localhost", changes[0].Old)
+	require.Equal(t, "example.com", changes[0].New)
+	require.Equal(t, "[redacted]", changes[1].Old)
+	require.Equal(t, "[redacted]", changes[1].New)
+	require.Equal(t, "RL.TLS.Cert", changes[2].Field.Name())
+	require.Equal(t, "example.com", conf.Host)
+	require.Equal(t, "hunter3", conf.Password)
+	require.Equal(t, "cert.pem", conf.TLS.Cert)
+	require.Equal(t, 1, conf.version)
+
+	os.Setenv("RL_PORT", "0")
+	os.Setenv("RL_HOST", "other.com")
+	_, err = cinfo.Reload()
+	require.Equal(t, "envconfig: RL.Port does not satisfy min=1", err.Error())
+	require.Equal(t, "example.com", conf.Host)
+	require.Equal(t, 80, conf.Port)
+
+	os.Setenv("RL_PORT", "81")
+	os.Setenv("RL_HOST", "forbidden")
+	_, err = cinfo.Reload()
+	require.Equal(t, "envconfig: RL: forbidden host", err.Error())
+	require.Equal(t, "example.com", conf.Host)
+	require.Equal(t, 80, conf.Port)
+
+	os.Setenv("RL_HOST", "example.com")
+	os.Setenv("RL_TLS_CERT", "")
+	changes, err = cinfo.Reload()
+	require.Nil(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, "RL.TLS.Cert", changes[1].Field.Name())
+	require.Equal(t, "cert.pem", changes[1].Old)
+	require.Equal(t, "", changes[1].New)
+	require.Nil(t, conf.TLS)
+	require.Equal(t, 81, conf.Port)
+
+	os.Setenv("RL_HOST", "")
+	os.Setenv("RL_PORT", "")
+	os.Setenv("RL_PASSWORD", "")
+}
+
+func TestReloadClosedGate(t *testing.T) {
+	var conf struct {
+		Metrics struct {
+			Enabled bool `envconfig:"optional"`
+			Addr    string
+		} `envconfig:"gate=Enabled"`
+	}
+
+	os.Setenv("RLG_METRICS_ENABLED", "true")
+	os.Setenv("RLG_METRICS_ADDR", ":9090")
+	defer os.Setenv("RLG_METRICS_ADDR", "")
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{Prefix: "RLG"})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, ":9090", conf.Metrics.Addr)
+
+	os.Setenv("RLG_METRICS_ENABLED", "false")
+	changes, err := cinfo.Reload()
+	require.Nil(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, "RLG.Metrics.Enabled", changes[0].Field.Name())
+	require.Equal(t, "RLG.Metrics.Addr", changes[1].Field.Name())
+	require.Equal(t, ":9090", changes[1].Old)
+	require.Equal(t, "", changes[1].New)
+	require.Equal(t, "", conf.Metrics.Addr)
+	require.Equal(t, "", (*cinfo)[1].Value())
+
+	os.Setenv("RLG_METRICS_ENABLED", "")
+}
+
+func TestReloadKeepsProvidedDefaults(t *testing.T) {
+	var conf struct {
+		ID      string `envconfig:"ZZ_ID,default=@random"`
+		Message string `envconfig:"ZZ_MESSAGE,reloadable"`
+	}
+
+	os.Setenv("ZZ_MESSAGE", "hello")
+	defer os.Setenv("ZZ_MESSAGE", "")
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{RestrictReload: true})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	id := conf.ID
+
+	changes, err := cinfo.Reload()
+	require.Nil(t, err)
+	require.Nil(t, changes)
+
+	os.Setenv("ZZ_MESSAGE", "bonjour")
+	changes, err = cinfo.Reload()
+	require.Nil(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "bonjour", conf.Message)
+	require.Equal(t, id, conf.ID)
+}
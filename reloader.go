@@ -0,0 +1,173 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Reloader calls Reload on a configuration when asked to, for example when files change,
+// and passes the result to the functions subscribed to it.
+type Reloader struct {
+	conf interface {
+		Reload() ([]Change, error)
+	}
+
+	mu   sync.Mutex
+	subs []func(changes []Change, err error)
+}
+
+// NewReloader returns a Reloader for conf, which is usually a *ConfInfo.
+func NewReloader(conf interface {
+	Reload() ([]Change, error)
+}) *Reloader {
+	return &Reloader{conf: conf}
+}
+
+// Subscribe makes the Reloader call fn after each reload which changed a field or failed.
+func (r *Reloader) Subscribe(fn func(changes []Change, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Reload reloads the configuration and notifies the subscribers. Reloads are never run concurrently.
+func (r *Reloader) Reload() ([]Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes, err := r.conf.Reload()
+	if len(changes) > 0 || err != nil {
+		for _, fn := range r.subs {
+			fn(changes, err)
+		}
+	}
+	return changes, err
+}
+
+// ReloadOn reloads the configuration whenever a value is received from ch, until ch is closed or stop is called.
+func (r *Reloader) ReloadOn(ch <-chan struct{}) (stop func()) {
+	return r.loop(func(done <-chan struct{}) bool {
+		select {
+		case <-done:
+			return false
+		case _, ok := <-ch:
+			return ok
+		}
+	})
+}
+
+// ReloadOnSignal reloads the configuration whenever the process receives one of sigs, SIGHUP if none is given,
+// until stop is called.
+func (r *Reloader) ReloadOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	stopLoop := r.loop(func(done <-chan struct{}) bool {
+		select {
+		case <-done:
+			return false
+		case <-ch:
+			return true
+		}
+	})
+	return func() {
+		signal.Stop(ch)
+		stopLoop()
+	}
+}
+
+// loop reloads the configuration each time wait returns true, until it returns false.
+// wait must return false once done is closed, which stop does.
+func (r *Reloader) loop(wait func(done <-chan struct{}) bool) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		for wait(done) {
+			_, _ = r.Reload()
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// WatchFiles reloads the configuration whenever one of the files or directories at paths changes,
+// checking them every interval until stop is called.
+// Symbolic links are followed, so that replacing the target of a link, as Kubernetes does when it updates a mounted secret, is seen as a change.
+// The entries of directories are watched, but not the directories below them.
+//
+// Without paths, WatchFiles watches the files named by the _FILE keys of the configuration, read with Options.FileKeys,
+// as returned by ConfInfo.Files.
+func (r *Reloader) WatchFiles(interval time.Duration, paths ...string) (stop func()) {
+	watched := func() []string { return paths }
+	if conf, ok := r.conf.(interface{ Files() []string }); ok && len(paths) == 0 {
+		watched = conf.Files
+	}
+
+	ticker := time.NewTicker(interval)
+	last := fingerprint(watched())
+
+	stopLoop := r.loop(func(done <-chan struct{}) bool {
+		for {
+			select {
+			case <-done:
+				return false
+			case <-ticker.C:
+			}
+
+			if cur := fingerprint(watched()); cur != last {
+				last = cur
+				return true
+			}
+		}
+	})
+	return func() {
+		ticker.Stop()
+		stopLoop()
+	}
+}
+
+// fingerprint describes the state of the files at paths, following symbolic links.
+func fingerprint(paths []string) string {
+	var buf strings.Builder
+	for _, path := range paths {
+		writeFingerprint(&buf, path)
+
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			entries, _ := filepath.Glob(filepath.Join(path, "*"))
+			sort.Strings(entries)
+			for _, entry := range entries {
+				writeFingerprint(&buf, entry)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func writeFingerprint(buf *strings.Builder, path string) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		fmt.Fprintf(buf, "%s: %v\n", path, err)
+		return
+	}
+
+	fi, err := os.Stat(target)
+	if err != nil {
+		fmt.Fprintf(buf, "%s: %v\n", path, err)
+		return
+	}
+	fmt.Fprintf(buf, "%s -> %s %d %d %v\n", path, target, fi.Size(), fi.ModTime().UnixNano(), fi.Mode())
+}
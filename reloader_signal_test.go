@@ -0,0 +1,51 @@
+//go:build !windows
+// +build !windows
+
+package envconfig_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloaderReloadOnSignal(t *testing.T) {
+	var conf struct {
+		Message string `envconfig:"RS_MESSAGE"`
+	}
+
+	os.Setenv("RS_MESSAGE", "hello")
+	defer os.Setenv("RS_MESSAGE", "")
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+
+	reloads := make(chan []envconfig.Change, 1)
+	r := envconfig.NewReloader(cinfo)
+	r.Subscribe(func(changes []envconfig.Change, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		reloads <- changes
+	})
+
+	stop := r.ReloadOnSignal()
+	defer stop()
+
+	os.Setenv("RS_MESSAGE", "bonjour")
+	require.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	select {
+	case changes := <-reloads:
+		require.Len(t, changes, 1)
+		require.Equal(t, "bonjour", changes[0].New)
+		require.Equal(t, "bonjour", conf.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after SIGHUP")
+	}
+}
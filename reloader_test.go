@@ -0,0 +1,139 @@
+package envconfig_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloaderWatchFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "envconfig")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	secrets := filepath.Join(dir, "secrets")
+
+	// lay the directory out like a Kubernetes secret volume: the file is a link through ..data to a versioned directory
+	writeVersion := func(version, token string) {
+		versionDir := filepath.Join(dir, version)
+		require.Nil(t, os.MkdirAll(versionDir, 0755))
+		require.Nil(t, ioutil.WriteFile(filepath.Join(versionDir, "token"), []byte(token+"\n"), 0644))
+
+		tmp := filepath.Join(secrets, "..data_tmp")
+		require.Nil(t, os.Symlink(versionDir, tmp))
+		require.Nil(t, os.Rename(tmp, filepath.Join(secrets, "..data")))
+	}
+	require.Nil(t, os.MkdirAll(secrets, 0755))
+	writeVersion("v1", "first")
+	require.Nil(t, os.Symlink(filepath.Join("..data", "token"), filepath.Join(secrets, "token")))
+
+	token := filepath.Join(secrets, "token")
+	os.Setenv("WATCHED_TOKEN_FILE", token)
+	defer os.Setenv("WATCHED_TOKEN_FILE", "")
+
+	var conf struct {
+		Token string `envconfig:"WATCHED_TOKEN"`
+	}
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{FileKeys: true})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.Equal(t, "first", conf.Token)
+	require.Equal(t, []string{token}, cinfo.Files())
+
+	reloads := make(chan []envconfig.Change, 1)
+	r := envconfig.NewReloader(cinfo)
+	r.Subscribe(func(changes []envconfig.Change, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		reloads <- changes
+	})
+
+	stop := r.WatchFiles(10 * time.Millisecond)
+	defer stop()
+
+	writeVersion("v2", "second")
+
+	select {
+	case changes := <-reloads:
+		require.Len(t, changes, 1)
+		require.Equal(t, "first", changes[0].Old)
+		require.Equal(t, "second", changes[0].New)
+		require.Equal(t, "second", conf.Token)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the secret changed")
+	}
+}
+
+type failingConf struct{}
+
+func (failingConf) Reload() ([]envconfig.Change, error) {
+	return nil, errors.New("invalid")
+}
+
+func TestReloaderNotifiesErrors(t *testing.T) {
+	var got error
+	r := envconfig.NewReloader(failingConf{})
+	r.Subscribe(func(changes []envconfig.Change, err error) {
+		got = err
+	})
+
+	_, err := r.Reload()
+	require.Equal(t, "invalid", err.Error())
+	require.Equal(t, "invalid", got.Error())
+}
+
+func TestReloaderReloadOn(t *testing.T) {
+	var conf struct {
+		Port    int    `envconfig:"RO_PORT"`
+		Message string `envconfig:"RO_MESSAGE,reloadable"`
+	}
+
+	os.Setenv("RO_PORT", "80")
+	os.Setenv("RO_MESSAGE", "hello")
+
+	cinfo, err := envconfig.ParseWithOptions(&conf, envconfig.Options{RestrictReload: true})
+	require.Nil(t, err)
+	require.Nil(t, cinfo.Read())
+	require.True(t, (*cinfo)[1].Reloadable())
+
+	type result struct {
+		changes []envconfig.Change
+		err     error
+	}
+	results := make(chan result, 1)
+	r := envconfig.NewReloader(cinfo)
+	r.Subscribe(func(changes []envconfig.Change, err error) {
+		results <- result{changes, err}
+	})
+
+	ch := make(chan struct{})
+	stop := r.ReloadOn(ch)
+	defer stop()
+
+	os.Setenv("RO_MESSAGE", "bonjour")
+	ch <- struct{}{}
+	res := <-results
+	require.Nil(t, res.err)
+	require.Len(t, res.changes, 1)
+	require.Equal(t, "bonjour", conf.Message)
+
+	os.Setenv("RO_PORT", "81")
+	os.Setenv("RO_MESSAGE", "hallo")
+	ch <- struct{}{}
+	res = <-results
+	require.Equal(t, "envconfig: Port cannot be reloaded", res.err.Error())
+	require.Len(t, res.changes, 2)
+	require.Equal(t, 80, conf.Port)
+	require.Equal(t, "bonjour", conf.Message)
+
+	os.Setenv("RO_PORT", "")
+	os.Setenv("RO_MESSAGE", "")
+}
@@ -0,0 +1,55 @@
+package envconfig
+
+import "reflect"
+
+// slot is a nil pointer field left nil by Read when none of the fields behind it is set, with Options.LeaveNil.
+type slot struct {
+	name   fieldName
+	target reflect.Value
+	alloc  reflect.Value
+	fields []*Field
+	set    bool
+}
+
+// readSlot returns the value the fields behind the nil pointer field target are read into,
+// along with a context for reading them only while one of them is set.
+func readSlot(target reflect.Value, name fieldName, ctx *context) (reflect.Value, *context) {
+	s := &slot{
+		name:   name,
+		target: target,
+		alloc:  reflect.New(target.Type().Elem()),
+	}
+	ctx.state.slots = append(ctx.state.slots, s)
+
+	sub := *ctx
+	sub.gate = &gate{parent: ctx.gate, slot: s}
+	return s.alloc.Elem(), &sub
+}
+
+// updateSlots points every slot with a field set, either from a key or by a default value, to its value, and sets the others to nil.
+func (st *state) updateSlots() error {
+	for _, s := range st.slots {
+		s.set = false
+		for _, fld := range s.fields {
+			str, _, err := fld.resolve(make(map[*Field]bool))
+			if err != nil {
+				return err
+			}
+			s.set = s.set || str != ""
+		}
+
+		if s.set {
+			s.target.Set(s.alloc)
+		} else {
+			s.target.Set(reflect.Zero(s.target.Type()))
+		}
+	}
+	return nil
+}
+
+func (s *slot) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.name.String() + " is set"
+}
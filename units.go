@@ -0,0 +1,220 @@
+package envconfig
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a number of bytes which can be written in a human readable form such as 512MiB or 1.5GB.
+type ByteSize uint64
+
+// Unmarshal parses a human readable byte size.
+func (b *ByteSize) Unmarshal(s string) error {
+	n, err := parseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(n)
+	return nil
+}
+
+// String returns the byte size using the largest unit which represents it exactly.
+func (b ByteSize) String() string {
+	return formatByteSize(uint64(b))
+}
+
+var byteUnits = map[string]uint64{
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"eb":  1e18,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+// parseByteSize parses a number optionally followed by a decimal (kB, MB, ...) or binary (KiB, MiB, ...) unit.
+// Units are case insensitive and a number without a unit is a number of bytes.
+func parseByteSize(s string) (uint64, error) {
+	str := strings.TrimSpace(s)
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(str)
+	}
+
+	num, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+	if unit == "" {
+		unit = "b"
+	}
+
+	mult, ok := byteUnits[unit]
+	if num == "" || !ok {
+		return 0, fmt.Errorf("envconfig: invalid byte size %q", s)
+	}
+
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > math.MaxUint64/mult {
+			return 0, fmt.Errorf("envconfig: byte size %q out of range", s)
+		}
+		return n * mult, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("envconfig: invalid byte size %q", s)
+	}
+	f *= float64(mult)
+	if f >= math.MaxUint64 {
+		return 0, fmt.Errorf("envconfig: byte size %q out of range", s)
+	}
+	return uint64(f), nil
+}
+
+var (
+	binaryUnits  = []string{"EiB", "PiB", "TiB", "GiB", "MiB", "KiB"}
+	decimalUnits = []string{"EB", "PB", "TB", "GB", "MB", "kB"}
+)
+
+func formatByteSize(n uint64) string {
+	if n == 0 {
+		return "0B"
+	}
+	for _, units := range [][]string{binaryUnits, decimalUnits} {
+		for _, unit := range units {
+			mult := byteUnits[strings.ToLower(unit)]
+			if n%mult == 0 {
+				return strconv.FormatUint(n/mult, 10) + unit
+			}
+		}
+	}
+	return strconv.FormatUint(n, 10) + "B"
+}
+
+// checkUnit returns an error if the integers of type t, which the values of fld are parsed into, cannot be read with the unit of fld.
+// Types parsed by an unmarshaler or a parser may use any unit.
+func (fld *Field) checkUnit(t reflect.Type) error {
+	switch {
+	case fld.unit == "" || fld.unit == "bytes":
+		return nil
+	case isUnmarshaler(t) || hasParser(fld.parsers, t) || isDurationField(t) || isTimeField(t):
+		return nil
+	case t == byteSliceType || isByteArray(t):
+		return nil
+	case isIntegerKind(t.Kind()):
+		return fmt.Errorf("envconfig: unknown unit %q", fld.unit)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return fld.checkUnit(t.Elem())
+	case reflect.Map:
+		if err := fld.checkUnit(t.Key()); err != nil {
+			return err
+		}
+		return fld.checkUnit(t.Elem())
+	}
+	return nil
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func parseByteSizeValue(v reflect.Value, str string) error {
+	n, err := parseByteSize(str)
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n > math.MaxInt64 || v.OverflowInt(int64(n)) {
+			return fmt.Errorf("envconfig: byte size %q overflows %s", str, v.Type())
+		}
+		v.SetInt(int64(n))
+	default:
+		if v.OverflowUint(n) {
+			return fmt.Errorf("envconfig: byte size %q overflows %s", str, v.Type())
+		}
+		v.SetUint(n)
+	}
+
+	return nil
+}
+
+var longDurationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// parseLongDuration parses a duration like time.ParseDuration does,
+// but also accepts the units d (24 hours) and w (7 days), for example 1w2d or 1.5d12h.
+func parseLongDuration(s string) (time.Duration, error) {
+	if !strings.ContainsAny(s, "dw") {
+		return time.ParseDuration(s)
+	}
+
+	str, neg := s, false
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	var d time.Duration
+	for str != "" {
+		i := strings.IndexFunc(str, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("time: invalid duration %q", s)
+		}
+		j := len(str)
+		if k := strings.IndexAny(str[i:], "0123456789."); k >= 0 {
+			j = i + k
+		}
+
+		num, unit := str[:i], str[i:j]
+		str = str[j:]
+
+		if mult, ok := longDurationUnits[unit]; ok {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("time: invalid duration %q", s)
+			}
+			v := f * float64(mult)
+			if v >= math.MaxInt64 || d > math.MaxInt64-time.Duration(v) {
+				return 0, fmt.Errorf("time: invalid duration %q", s)
+			}
+			d += time.Duration(v)
+			continue
+		}
+
+		part, err := time.ParseDuration(num + unit)
+		if err != nil || d > math.MaxInt64-part {
+			return 0, fmt.Errorf("time: invalid duration %q", s)
+		}
+		d += part
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,76 @@
+package envconfig
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]uint64{
+		"0":       0,
+		"512":     512,
+		"512B":    512,
+		"1kB":     1000,
+		"1KiB":    1024,
+		"512MiB":  512 << 20,
+		"512mib":  512 << 20,
+		"1.5GB":   1500000000,
+		"1.5GiB":  3 << 29,
+		"2 TB":    2e12,
+		"16EiB":   0,
+		"16 byte": 0,
+		"GB":      0,
+	}
+
+	for s, expected := range cases {
+		n, err := parseByteSize(s)
+		if expected == 0 && s != "0" {
+			require.NotNil(t, err, s)
+			continue
+		}
+		require.Nil(t, err, s)
+		require.Equal(t, expected, n, s)
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	require.Equal(t, "0B", formatByteSize(0))
+	require.Equal(t, "512MiB", formatByteSize(512<<20))
+	require.Equal(t, "1536MiB", formatByteSize(3<<29))
+	require.Equal(t, "1500MB", formatByteSize(1500000000))
+	require.Equal(t, "1001B", formatByteSize(1001))
+	require.Equal(t, "1GiB", ByteSize(1<<30).String())
+}
+
+func TestParseLongDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"90s":     90 * time.Second,
+		"1d":      24 * time.Hour,
+		"1w2d":    9 * 24 * time.Hour,
+		"1.5d12h": 48 * time.Hour,
+		"-1d1h":   -25 * time.Hour,
+		"2w30m":   14*24*time.Hour + 30*time.Minute,
+
+		"106751d23h47m16.854775807s": math.MaxInt64,
+	}
+
+	for s, expected := range cases {
+		d, err := parseLongDuration(s)
+		require.Nil(t, err, s)
+		require.Equal(t, expected, d, s)
+	}
+
+	for _, s := range []string{"d", "1x2d", "1d2", "w1d"} {
+		_, err := parseLongDuration(s)
+		require.NotNil(t, err, s)
+	}
+
+	for _, s := range []string{"400000w", "15251w", "106751d23h47m16.854775808s"} {
+		_, err := parseLongDuration(s)
+		require.Equal(t, fmt.Sprintf("time: invalid duration %q", s), err.Error(), s)
+	}
+}
@@ -0,0 +1,257 @@
+package envconfig
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// rule is a constraint set with a tag option such as min=1, checked after the field is read.
+type rule struct {
+	name  string
+	arg   string
+	check func(v reflect.Value) bool
+}
+
+func (r *rule) String() string {
+	if r.arg == "" {
+		return r.name
+	}
+	return r.name + "=" + r.arg
+}
+
+func isRule(name string) bool {
+	switch name {
+	case "min", "max", "len", "oneof", "regex", "nonzero", "port", "hostport":
+		return true
+	}
+	return false
+}
+
+// ruleTakesArg returns whether the rule name is written with an argument, as in min=1, rather than alone, as in nonzero.
+func ruleTakesArg(name string) bool {
+	switch name {
+	case "nonzero", "port", "hostport":
+		return false
+	}
+	return true
+}
+
+// compileRules prepares the rules of fld for checking, returning an error if a rule does not apply to the type of the field.
+func (fld *Field) compileRules() error {
+	t := fld.value.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for _, r := range fld.rules {
+		var err error
+		switch r.name {
+		case "min", "max":
+			r.check, err = fld.compileBound(t, r.name, r.arg)
+		case "len":
+			r.check, err = compileLen(t, r.arg)
+		case "nonzero":
+			r.check = func(v reflect.Value) bool {
+				return !v.IsZero()
+			}
+		case "oneof":
+			choices := strings.Split(r.arg, "|")
+			r.check, err = compileScalar(t, func(v reflect.Value) bool {
+				s := fmt.Sprint(v.Interface())
+				for _, c := range choices {
+					if s == c {
+						return true
+					}
+				}
+				return false
+			})
+		case "regex":
+			var re *regexp.Regexp
+			if re, err = regexp.Compile("^(?:" + r.arg + ")$"); err != nil {
+				break
+			}
+			r.check, err = compileString(t, re.MatchString)
+		case "port":
+			if isIntegerKind(elemType(t).Kind()) {
+				r.check, err = compileScalar(t, func(v reflect.Value) bool {
+					return isPort(fmt.Sprint(v.Interface()))
+				})
+			} else {
+				r.check, err = compileString(t, isPort)
+			}
+		case "hostport":
+			r.check, err = compileString(t, func(s string) bool {
+				_, port, err := net.SplitHostPort(s)
+				return err == nil && isPort(port)
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("envconfig: %s: invalid rule %s: %v", fld.Name(), r, err)
+		}
+	}
+
+	return nil
+}
+
+// checkRules returns an error naming the first rule the value of fld does not satisfy.
+// Only nonzero is checked when no value was read for the field.
+func (fld *Field) checkRules() error {
+	v := fld.value
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+		} else {
+			v = v.Elem()
+		}
+	}
+
+	for _, r := range fld.rules {
+		if fld.strValue == "" && r.name != "nonzero" {
+			continue
+		}
+		if !r.check(v) {
+			return fmt.Errorf("envconfig: %s does not satisfy %s", fld.Name(), r)
+		}
+	}
+	return nil
+}
+
+// Rules returns the constraints set on this field with tag options such as min=1 or oneof=a|b.
+func (fld *Field) Rules() []string {
+	var res []string
+	for _, r := range fld.rules {
+		res = append(res, r.String())
+	}
+	return res
+}
+
+// elemType returns the element type of slices and arrays other than byte slices and arrays, and t otherwise.
+func elemType(t reflect.Type) reflect.Type {
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8 {
+		return t.Elem()
+	}
+	return t
+}
+
+// compileScalar returns a check applying fn to a value, or to each element of a slice or array.
+func compileScalar(t reflect.Type, fn func(v reflect.Value) bool) (func(v reflect.Value) bool, error) {
+	switch elemType(t).Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+	default:
+		return nil, fmt.Errorf("not supported for %v", t)
+	}
+
+	if elemType(t) == t {
+		return fn, nil
+	}
+	return func(v reflect.Value) bool {
+		for i := 0; i < v.Len(); i++ {
+			if !fn(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func compileString(t reflect.Type, fn func(s string) bool) (func(v reflect.Value) bool, error) {
+	if elemType(t).Kind() != reflect.String {
+		return nil, fmt.Errorf("not supported for %v", t)
+	}
+	return compileScalar(t, func(v reflect.Value) bool {
+		return fn(v.String())
+	})
+}
+
+func length(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+func compileLen(t reflect.Type, arg string) (func(v reflect.Value) bool, error) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return nil, fmt.Errorf("not supported for %v", t)
+	}
+
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) bool {
+		return length(v) == n
+	}, nil
+}
+
+// compileBound returns the check for min= or max=, which bound the value of numbers and times
+// and the length of strings, slices and maps.
+func (fld *Field) compileBound(t reflect.Type, name, arg string) (func(v reflect.Value) bool, error) {
+	bound := reflect.New(t).Elem()
+
+	var cmp func(v reflect.Value) int
+	switch {
+	case isTimeField(t):
+		if err := parseTime(bound, arg, fld.layout, fld.tz); err != nil {
+			return nil, err
+		}
+		b := bound.Convert(timeType).Interface().(time.Time)
+		cmp = func(v reflect.Value) int {
+			u := v.Convert(timeType).Interface().(time.Time)
+			return compare(u.Before(b), u.After(b))
+		}
+	case t.Kind() == reflect.String || t.Kind() == reflect.Slice || t.Kind() == reflect.Map || t.Kind() == reflect.Array:
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		cmp = func(v reflect.Value) int {
+			return length(v) - n
+		}
+	case isIntegerKind(t.Kind()) || t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		if err := fld.parseValue(bound, arg); err != nil {
+			return nil, err
+		}
+		cmp = func(v reflect.Value) int {
+			switch {
+			case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+				return compare(v.Float() < bound.Float(), v.Float() > bound.Float())
+			case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+				return compare(v.Uint() < bound.Uint(), v.Uint() > bound.Uint())
+			default:
+				return compare(v.Int() < bound.Int(), v.Int() > bound.Int())
+			}
+		}
+	default:
+		return nil, fmt.Errorf("not supported for %v", t)
+	}
+
+	if name == "min" {
+		return func(v reflect.Value) bool { return cmp(v) >= 0 }, nil
+	}
+	return func(v reflect.Value) bool { return cmp(v) <= 0 }, nil
+}
+
+func compare(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+func isPort(s string) bool {
+	n, err := strconv.ParseUint(s, 10, 16)
+	return err == nil && n > 0
+}
@@ -0,0 +1,116 @@
+package envconfig_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedConfig struct {
+	Workers  int           `envconfig:"min=1,max=64"`
+	Ratio    float64       `envconfig:"min=0,max=1"`
+	Timeout  time.Duration `envconfig:"min=1s,max=1m"`
+	Cache    uint64        `envconfig:"unit=bytes,max=1GiB"`
+	Code     string        `envconfig:"len=3"`
+	Tags     []string      `envconfig:"min=1,oneof=a|b|c"`
+	Level    string        `envconfig:"oneof=debug|info|warn"`
+	Name     string        `envconfig:"regex=[a-z]+(-[a-z]+)*"`
+	Token    string        `envconfig:"nonzero,optional"`
+	Port     int           `envconfig:"port"`
+	Listen   string        `envconfig:"hostport"`
+	Deadline time.Time     `envconfig:"layout=DateOnly,min=2020-01-01"`
+	Note     string        `envconfig:"optional,len=5"`
+}
+
+func TestValidationRules(t *testing.T) {
+	valid := map[string]string{
+		"WORKERS":  "8",
+		"RATIO":    "0.5",
+		"TIMEOUT":  "30s",
+		"CACHE":    "512MiB",
+		"CODE":     "abc",
+		"TAGS":     "a,c",
+		"LEVEL":    "info",
+		"NAME":     "my-service",
+		"TOKEN":    "secret",
+		"PORT":     "8080",
+		"LISTEN":   ":8080",
+		"DEADLINE": "2024-06-01",
+		"NOTE":     "",
+	}
+	setenv := func(overrides map[string]string) {
+		for k, v := range valid {
+			os.Setenv(k, v)
+		}
+		for k, v := range overrides {
+			os.Setenv(k, v)
+		}
+	}
+
+	setenv(nil)
+	err := envconfig.Init(&validatedConfig{})
+	require.Nil(t, err)
+
+	failures := []struct {
+		key, value, err string
+	}{
+		{"WORKERS", "0", "envconfig: Workers does not satisfy min=1"},
+		{"WORKERS", "65", "envconfig: Workers does not satisfy max=64"},
+		{"RATIO", "1.5", "envconfig: Ratio does not satisfy max=1"},
+		{"TIMEOUT", "2m", "envconfig: Timeout does not satisfy max=1m"},
+		{"CACHE", "2GiB", "envconfig: Cache does not satisfy max=1GiB"},
+		{"CODE", "abcd", "envconfig: Code does not satisfy len=3"},
+		{"TAGS", "a,d", "envconfig: Tags does not satisfy oneof=a|b|c"},
+		{"LEVEL", "trace", "envconfig: Level does not satisfy oneof=debug|info|warn"},
+		{"NAME", "My-Service", "envconfig: Name does not satisfy regex=[a-z]+(-[a-z]+)*"},
+		{"TOKEN", "", "envconfig: Token does not satisfy nonzero"},
+		{"PORT", "70000", "envconfig: Port does not satisfy port"},
+		{"LISTEN", "localhost", "envconfig: Listen does not satisfy hostport"},
+		{"DEADLINE", "2019-12-31", "envconfig: Deadline does not satisfy min=2020-01-01"},
+		{"NOTE", "abc", "envconfig: Note does not satisfy len=5"},
+	}
+
+	for _, f := range failures {
+		setenv(map[string]string{f.key: f.value})
+		err = envconfig.Init(&validatedConfig{})
+		require.NotNil(t, err, f.key)
+		require.Equal(t, f.err, err.Error())
+	}
+
+	for k := range valid {
+		os.Setenv(k, "")
+	}
+}
+
+func TestInvalidValidationRules(t *testing.T) {
+	var conf struct {
+		Enabled bool `envconfig:"min=1"`
+	}
+	_, err := envconfig.Parse(&conf)
+	require.Equal(t, "envconfig: Enabled: invalid rule min=1: not supported for bool", err.Error())
+
+	var conf2 struct {
+		Workers int `envconfig:"max=many"`
+	}
+	_, err = envconfig.Parse(&conf2)
+	require.Equal(t, `envconfig: Workers: invalid rule max=many: strconv.ParseInt: parsing "many": invalid syntax`, err.Error())
+
+	var conf3 struct {
+		Name string `envconfig:"regex=[a-z"`
+	}
+	_, err = envconfig.Parse(&conf3)
+	require.Equal(t, "envconfig: Name: invalid rule regex=[a-z: error parsing regexp: missing closing ]: `[a-z)$`", err.Error())
+}
+
+func TestValidationRulesAccessor(t *testing.T) {
+	var conf struct {
+		Port int `envconfig:"default=8080,port,min=1024"`
+	}
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+	require.Equal(t, []string{"port", "min=1024"}, (*cinfo)[0].Rules())
+}
@@ -0,0 +1,75 @@
+//go:build go1.19
+// +build go1.19
+
+package envconfig
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Value holds a configuration struct of type T which can be read while it is reloaded.
+// Load returns the current configuration, and Reload replaces it with a new one at once,
+// so that readers never see a configuration which is partly reloaded.
+type Value[T any] struct {
+	mu    sync.Mutex
+	cinfo *ConfInfo
+	cur   atomic.Pointer[T]
+}
+
+// NewValue reads a configuration struct of type T with opts and returns a Value holding it.
+// T must be a struct type.
+func NewValue[T any](opts Options) (*Value[T], error) {
+	cinfo, err := ParseWithOptions(new(T), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Value[T]{cinfo: cinfo}
+	if len(*cinfo) == 0 {
+		v.cur.Store(new(T))
+		return v, nil
+	}
+
+	_, next, err := cinfo.reload(false)
+	if err != nil {
+		return nil, err
+	}
+	v.cur.Store(next.Interface().(*T))
+	return v, nil
+}
+
+// Load returns the current configuration. It must not be modified.
+func (v *Value[T]) Load() *T {
+	return v.cur.Load()
+}
+
+// Reload reads the configuration again like ConfInfo.Reload and, if it succeeds,
+// makes the new configuration the one returned by Load. Configurations returned by Load before are left unchanged.
+func (v *Value[T]) Reload() ([]Change, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(*v.cinfo) == 0 {
+		return nil, nil
+	}
+
+	changes, next, err := v.cinfo.reload(true)
+	if err != nil {
+		return changes, err
+	}
+	v.cur.Store(next.Interface().(*T))
+	return changes, nil
+}
+
+// ConfInfo returns the ConfInfo describing the fields of the configuration, for example to generate documentation.
+// The fields report the values of the current configuration.
+// Use Reload rather than the Read method of the ConfInfo to read the configuration again.
+func (v *Value[T]) ConfInfo() *ConfInfo {
+	return v.cinfo
+}
+
+// Files returns the files the configuration is read from with Options.FileKeys, like ConfInfo.Files.
+func (v *Value[T]) Files() []string {
+	return v.cinfo.Files()
+}
@@ -0,0 +1,68 @@
+//go:build go1.19
+// +build go1.19
+
+package envconfig_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type valueConfig struct {
+	Host  string
+	Ports []int
+}
+
+func TestValue(t *testing.T) {
+	os.Setenv("VAL_HOST", "localhost")
+	os.Setenv("VAL_PORTS", "80,443")
+
+	v, err := envconfig.NewValue[valueConfig](envconfig.Options{Prefix: "VAL"})
+	require.Nil(t, err)
+
+	first := v.Load()
+	require.Equal(t, &valueConfig{Host: "localhost", Ports: []int{80, 443}}, first)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				conf := v.Load()
+				if conf.Host == "example.com" && len(conf.Ports) != 1 {
+					t.Errorf("half-updated configuration %v", conf)
+				}
+			}
+		}()
+	}
+
+	os.Setenv("VAL_HOST", "example.com")
+	os.Setenv("VAL_PORTS", "8080")
+	changes, err := v.Reload()
+	wg.Wait()
+
+	require.Nil(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, &valueConfig{Host: "example.com", Ports: []int{8080}}, v.Load())
+	require.Equal(t, &valueConfig{Host: "localhost", Ports: []int{80, 443}}, first)
+	require.Equal(t, "example.com", (*v.ConfInfo())[0].Value())
+
+	os.Setenv("VAL_PORTS", "foo")
+	_, err = v.Reload()
+	require.NotNil(t, err)
+	require.Equal(t, "example.com", v.Load().Host)
+
+	os.Setenv("VAL_HOST", "")
+	os.Setenv("VAL_PORTS", "")
+
+	_, err = envconfig.NewValue[valueConfig](envconfig.Options{Prefix: "VAL"})
+	require.Equal(t, "envconfig: keys VAL_HOST, val_host not found", err.Error())
+
+	_, err = envconfig.NewValue[int](envconfig.Options{})
+	require.Equal(t, envconfig.ErrInvalidValueKind, err)
+}
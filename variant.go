@@ -0,0 +1,188 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	variantsMu sync.RWMutex
+	variants   = make(map[reflect.Type]map[string]variantImpl)
+)
+
+type variantImpl struct {
+	typ reflect.Type
+	ptr bool
+}
+
+// Register makes impl available as the implementation named name of the interface iface.
+// iface must be a nil pointer to the interface type, for example (*Storage)(nil).
+// impl must be a struct or a pointer to a struct implementing the interface; fields of the interface type are set to a value of the same kind.
+//
+// A field of a registered interface type is configured in two steps.
+// The key made of the field name followed by Driver, STORAGE_DRIVER for a field named Storage, selects the implementation by name.
+// The fields of the selected implementation are then read like a nested struct named after the implementation,
+// so STORAGE_S3_BUCKET sets the Bucket field of the implementation registered as s3.
+//
+// Register panics if it is called twice with the same name for the same interface, or if impl does not implement the interface.
+func Register(iface interface{}, name string, impl interface{}) {
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		panic("envconfig: Register iface must be a nil pointer to an interface")
+	}
+	ifaceType = ifaceType.Elem()
+
+	implType := reflect.TypeOf(impl)
+	v := variantImpl{typ: implType}
+	if implType != nil && implType.Kind() == reflect.Ptr {
+		v = variantImpl{typ: implType.Elem(), ptr: true}
+	}
+	if implType == nil || v.typ.Kind() != reflect.Struct {
+		panic("envconfig: Register impl must be a struct or a pointer to a struct")
+	}
+	if !implType.Implements(ifaceType) {
+		panic(fmt.Sprintf("envconfig: Register %v does not implement %v", implType, ifaceType))
+	}
+
+	variantsMu.Lock()
+	defer variantsMu.Unlock()
+
+	if variants[ifaceType] == nil {
+		variants[ifaceType] = make(map[string]variantImpl)
+	}
+	if _, dup := variants[ifaceType][name]; dup {
+		panic(fmt.Sprintf("envconfig: Register called twice for %v implementation %s", ifaceType, name))
+	}
+	variants[ifaceType][name] = v
+}
+
+func hasVariants(t reflect.Type) bool {
+	variantsMu.RLock()
+	defer variantsMu.RUnlock()
+	return len(variants[t]) > 0
+}
+
+// variant is an implementation of an interface field which can be selected by its discriminator field.
+type variant struct {
+	name     string
+	target   reflect.Value
+	instance reflect.Value
+	ptr      bool
+}
+
+// readVariants adds the discriminator field of the interface field value named name,
+// followed by the fields of every registered implementation.
+func readVariants(value reflect.Value, name string, t *tag, ctx *context) error {
+	variantsMu.RLock()
+	impls := variants[value.Type()]
+	names := make([]string, 0, len(impls))
+	for n := range impls {
+		names = append(names, n)
+	}
+	variantsMu.RUnlock()
+	sort.Strings(names)
+
+	ctx = ctx.sub(name, &tag{prefix: t.prefix, inline: t.inline})
+	selector, err := newField("Driver", reflect.New(reflect.TypeOf("")).Elem(), t, ctx)
+	if err != nil {
+		return err
+	}
+	ctx.config.append(selector)
+
+	for _, n := range names {
+		impl := impls[n]
+		v := &variant{
+			name:     n,
+			target:   value,
+			instance: reflect.New(impl.typ),
+			ptr:      impl.ptr,
+		}
+		selector.variants = append(selector.variants, v)
+
+		sub := *ctx
+		sub.name = ctx.name.Append(n)
+		sub.path = ctx.path.Append(n)
+		sub.gate = &gate{parent: ctx.gate, field: selector, value: n}
+		if err := readStruct(v.instance.Elem(), &sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setVariants sets every interface field behind an open gate to the implementation selected by its discriminator field.
+// Implementations which are not pointers are copied, so this is done again once AfterRead may have changed them.
+func (cinfo *ConfInfo) setVariants() error {
+	for _, fld := range *cinfo {
+		if fld.variants != nil && fld.gate.isOpen() {
+			if err := fld.setVariant(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setVariant sets the interface field to the implementation selected by the discriminator field fld.
+func (fld *Field) setVariant() error {
+	name := fld.value.String()
+	target := fld.variants[0].target
+
+	if name == "" {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	for _, v := range fld.variants {
+		if v.name != name {
+			continue
+		}
+		if v.ptr {
+			target.Set(v.instance)
+		} else {
+			target.Set(v.instance.Elem())
+		}
+		return nil
+	}
+
+	return fmt.Errorf("envconfig: unknown %s %q, expected one of %s", fld.Name(), name, strings.Join(fld.Choices(), ", "))
+}
+
+// gate makes the fields behind it read only while the value of field equals value,
+// or, for a gate on a slot, while the slot is set.
+type gate struct {
+	parent *gate
+	field  *Field
+	value  string
+	slot   *slot
+}
+
+func (g *gate) isOpen() bool {
+	for ; g != nil; g = g.parent {
+		if g.slot != nil && !g.slot.set {
+			return false
+		}
+		if g.slot == nil && fmt.Sprint(g.field.value.Interface()) != g.value {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *gate) String() string {
+	if g == nil {
+		return ""
+	}
+	cond := g.slot.String()
+	if g.slot == nil {
+		cond = g.field.displayKey() + "=" + g.value
+	}
+	if parent := g.parent.String(); parent != "" {
+		return parent + " and " + cond
+	}
+	return cond
+}
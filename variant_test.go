@@ -0,0 +1,89 @@
+package envconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/JamesStewy/envconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type storage interface {
+	Location() string
+}
+
+type s3Storage struct {
+	Bucket string
+	Region string `envconfig:"default=us-east-1"`
+}
+
+func (s *s3Storage) Location() string { return "s3://" + s.Bucket + "@" + s.Region }
+
+type diskStorage struct {
+	Path string
+}
+
+func (s diskStorage) Location() string { return "file://" + s.Path }
+
+func init() {
+	envconfig.Register((*storage)(nil), "s3", &s3Storage{})
+	envconfig.Register((*storage)(nil), "disk", diskStorage{})
+}
+
+func TestVariants(t *testing.T) {
+	var conf struct {
+		Storage storage
+		Backup  storage `envconfig:"optional"`
+	}
+
+	os.Setenv("STORAGE_DRIVER", "s3")
+	os.Setenv("STORAGE_S3_BUCKET", "assets")
+	os.Setenv("STORAGE_DISK_PATH", "")
+
+	cinfo, err := envconfig.Parse(&conf)
+	require.Nil(t, err)
+
+	var keys []string
+	for _, fld := range *cinfo {
+		keys = append(keys, fld.Keys()[0])
+	}
+	require.Equal(t, []string{
+		"STORAGE_DRIVER", "STORAGE_DISK_PATH", "STORAGE_S3_BUCKET", "STORAGE_S3_REGION",
+		"BACKUP_DRIVER", "BACKUP_DISK_PATH", "BACKUP_S3_BUCKET", "BACKUP_S3_REGION",
+	}, keys)
+	require.Equal(t, []string{"disk", "s3"}, (*cinfo)[0].Choices())
+	require.Equal(t, "STORAGE_DRIVER=s3", (*cinfo)[2].Condition())
+
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "s3://assets@us-east-1", conf.Storage.Location())
+	require.Nil(t, conf.Backup)
+
+	os.Setenv("BACKUP_DRIVER", "disk")
+	err = cinfo.Read()
+	require.Equal(t, "envconfig: keys BACKUP_DISK_PATH, backup_disk_path not found", err.Error())
+
+	os.Setenv("BACKUP_DISK_PATH", "/var/backups")
+	err = cinfo.Read()
+	require.Nil(t, err)
+	require.Equal(t, "file:///var/backups", conf.Backup.Location())
+
+	os.Setenv("BACKUP_DRIVER", "ftp")
+	err = cinfo.Read()
+	require.Equal(t, `envconfig: unknown Backup.Driver "ftp", expected one of disk, s3`, err.Error())
+
+	os.Setenv("BACKUP_DRIVER", "")
+	os.Setenv("BACKUP_DISK_PATH", "")
+}
+
+func TestRegisterPanics(t *testing.T) {
+	require.Panics(t, func() {
+		envconfig.Register((*storage)(nil), "s3", &s3Storage{})
+	})
+	require.Panics(t, func() {
+		envconfig.Register((*storage)(nil), "s3ByValue", s3Storage{})
+	})
+	require.Panics(t, func() {
+		envconfig.Register(storage(nil), "none", diskStorage{})
+	})
+}